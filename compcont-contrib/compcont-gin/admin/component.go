@@ -0,0 +1,408 @@
+package admin
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-compcont/compcont/compcont"
+	compcontjwt "github.com/go-compcont/compcont/compcont-contrib/compcont-jwt"
+)
+
+type Config struct {
+	Gin         compcont.Ref[gin.IRouter]                                  `ccf:"gin"`          // 挂载管理接口的gin组件
+	RoutePrefix string                                                     `ccf:"route_prefix"` // 路由前缀，默认为 /compcont
+	JWT         *compcont.TypedComponentConfig[any, compcontjwt.JWTAuther] `ccf:"jwt"`          // 设置后请求需要携带 Authorization: Bearer <token>
+	Insecure    bool                                                       `ccf:"insecure"`     // 未设置jwt时必须显式设置为true才会挂载管理接口，此时全部接口无需认证
+}
+
+const TypeID compcont.ComponentTypeID = "contrib.gin-admin"
+
+const DefaultRoutePrefix = "/compcont"
+
+// 组件的健康状况
+type ComponentHealth struct {
+	Path  string                  `json:"path"`
+	State compcont.ComponentState `json:"state"`
+	Error string                  `json:"error,omitempty"`
+}
+
+// HealthReport 容器树的健康报告，存在创建失败的组件时Status为failed
+type HealthReport struct {
+	Status     string            `json:"status"`
+	Components int               `json:"components"`
+	Failed     []ComponentHealth `json:"failed,omitempty"`
+	Pending    []ComponentHealth `json:"pending,omitempty"` // 正在创建或销毁的组件
+}
+
+// 一次组件加载耗时的JSON表示
+type profileEntry struct {
+	Path   string                   `json:"path"`
+	TypeID compcont.ComponentTypeID `json:"type,omitempty"`
+	Begin  time.Time                `json:"begin"`
+	Decode time.Duration            `json:"decode"`
+	Create time.Duration            `json:"create"`
+	Start  time.Duration            `json:"start"`
+	Total  time.Duration            `json:"total"`
+	Error  string                   `json:"error,omitempty"`
+}
+
+// Admin 以HTTP接口暴露容器树的只读信息，并支持触发热重载
+type Admin struct {
+	root     compcont.IComponentContainer
+	snapshot compcont.ISnapshotContainer // 根容器的快照，要求根容器实现 compcont.ISnapshotContainer
+	auther   compcontjwt.JWTAuther
+	key      mountKey
+}
+
+type mountKey struct {
+	router gin.IRouter
+	prefix string
+}
+
+// gin无法移除已注册的路由，同一个路由前缀的路由只注册一次，请求转发给最后挂载且尚未关闭的Admin，
+// 使依赖的gin、jwt等组件被重建或热重载时可以重新挂载，热重载回滚时恢复之前的Admin
+var (
+	mountsMu sync.RWMutex
+	mounts   = map[mountKey][]*Admin{}
+)
+
+// New 将管理接口挂载到gin组件上，暴露的是组件所在容器树的根容器。
+// 未设置jwt时需要显式设置insecure
+//
+//	GET  {prefix}/tree                 容器树快照
+//	GET  {prefix}/components/*path     单个组件的详情，配置中的敏感字段已隐藏
+//	GET  {prefix}/health               健康报告，存在创建失败的组件时返回503
+//	GET  {prefix}/graph?format=dot     依赖图，默认为JSON格式
+//	GET  {prefix}/profile?format=text  启动耗时报告，默认为JSON格式，容器未设置Profiler时返回404
+//	POST {prefix}/reload/*path         重新读取子容器的配置来源并热重载，容器需实现 compcont.ISourceContainer
+//	POST {prefix}/rebuild/*path        以当前配置重建一个组件及依赖它的组件
+func New(ctx compcont.Context, cfg Config) (a *Admin, err error) {
+	if cfg.JWT == nil && !cfg.Insecure {
+		err = fmt.Errorf("%w, jwt is required unless insecure is set", compcont.ErrComponentConfigInvalid)
+		return
+	}
+	g, err := cfg.Gin.Load(ctx)
+	if err != nil {
+		return
+	}
+	var auther compcontjwt.JWTAuther
+	if cfg.JWT != nil {
+		component, err1 := cfg.JWT.LoadComponent(ctx.Container)
+		if err1 != nil {
+			err = err1
+			return
+		}
+		auther = component.Instance
+	}
+	prefix := cfg.RoutePrefix
+	if prefix == "" {
+		prefix = DefaultRoutePrefix
+	}
+
+	root := ctx.Container
+	for root.GetParent() != nil {
+		root = root.GetParent()
+	}
+	snapshot, ok := root.(compcont.ISnapshotContainer)
+	if !ok {
+		err = fmt.Errorf("%w, root container %T does not support snapshots", compcont.ErrComponentTypeMismatch, root)
+		return
+	}
+	a = &Admin{root: root, snapshot: snapshot, auther: auther, key: mountKey{router: g.Instance, prefix: prefix}}
+
+	mountsMu.Lock()
+	defer mountsMu.Unlock()
+	if _, ok := mounts[a.key]; !ok {
+		registerRoutes(a.key)
+	}
+	mounts[a.key] = append(mounts[a.key], a)
+	return
+}
+
+// 注册管理接口的路由，路由在之前挂载的Admin全部关闭后仍然保留在gin中，此时gin会因重复注册而panic，
+// 保留的路由按key查找当前的Admin，可以直接复用
+func registerRoutes(key mountKey) {
+	defer func() {
+		if r := recover(); r != nil && !strings.Contains(fmt.Sprint(r), "already registered") {
+			panic(r)
+		}
+	}()
+	group := key.router.Group(key.prefix)
+	group.GET("/tree", handle(key, (*Admin).tree))
+	group.GET("/components/*path", handle(key, (*Admin).component))
+	group.GET("/health", handle(key, (*Admin).health))
+	group.GET("/graph", handle(key, (*Admin).graph))
+	group.GET("/profile", handle(key, (*Admin).profile))
+	group.POST("/reload/*path", handle(key, (*Admin).reload))
+	group.POST("/rebuild/*path", handle(key, (*Admin).rebuild))
+}
+
+// Close 卸载管理接口，之后的请求由之前挂载且尚未关闭的Admin处理
+func (a *Admin) Close() {
+	mountsMu.Lock()
+	defer mountsMu.Unlock()
+	admins := slices.DeleteFunc(mounts[a.key], func(admin *Admin) bool { return admin == a })
+	if len(admins) == 0 {
+		delete(mounts, a.key)
+		return
+	}
+	mounts[a.key] = admins
+}
+
+// 将请求转发给key上当前生效的Admin
+func handle(key mountKey, handler func(*Admin, *gin.Context)) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		mountsMu.RLock()
+		var a *Admin
+		if admins := mounts[key]; len(admins) > 0 {
+			a = admins[len(admins)-1]
+		}
+		mountsMu.RUnlock()
+		if a == nil {
+			abortWithError(c, http.StatusServiceUnavailable, errors.New("admin is not available"))
+			return
+		}
+		if a.auther != nil {
+			token, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+			if !ok || !a.auther.Verify(token) {
+				c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+				return
+			}
+		}
+		handler(a, c)
+	}
+}
+
+func abortWithError(c *gin.Context, code int, err error) {
+	c.AbortWithStatusJSON(code, gin.H{"error": err.Error()})
+}
+
+func (a *Admin) tree(c *gin.Context) {
+	c.JSON(http.StatusOK, a.snapshot.Snapshot())
+}
+
+func (a *Admin) component(c *gin.Context) {
+	path := splitPath(c.Param("path"))
+	snapshot, ok := findSnapshot(a.snapshot.Snapshot(), path)
+	if !ok {
+		abortWithError(c, http.StatusNotFound, fmt.Errorf("component %s not found", c.Param("path")))
+		return
+	}
+	c.JSON(http.StatusOK, snapshot)
+}
+
+func (a *Admin) health(c *gin.Context) {
+	report := HealthReport{Status: "ok"}
+	var walk func(snapshot compcont.ContainerSnapshot)
+	walk = func(snapshot compcont.ContainerSnapshot) {
+		for _, component := range snapshot.Components {
+			report.Components++
+			health := ComponentHealth{Path: formatPath(component.Path), State: component.State, Error: component.LastError}
+			switch component.State {
+			case compcont.StateFailed:
+				report.Failed = append(report.Failed, health)
+			case compcont.StateCreating, compcont.StateDestroying:
+				report.Pending = append(report.Pending, health)
+			}
+			if component.Container != nil {
+				walk(*component.Container)
+			}
+		}
+	}
+	walk(a.snapshot.Snapshot())
+	code := http.StatusOK
+	if len(report.Failed) > 0 {
+		report.Status = "failed"
+		code = http.StatusServiceUnavailable
+	}
+	c.JSON(code, report)
+}
+
+func (a *Admin) graph(c *gin.Context) {
+	graph := a.snapshot.Snapshot().Graph()
+	switch c.DefaultQuery("format", "json") {
+	case "dot":
+		c.String(http.StatusOK, graph.DOT())
+	case "json":
+		c.JSON(http.StatusOK, graph)
+	default:
+		abortWithError(c, http.StatusBadRequest, fmt.Errorf("unsupported format %s", c.Query("format")))
+	}
+}
+
+func (a *Admin) profile(c *gin.Context) {
+	container, ok := a.root.(interface{ Profiler() *compcont.Profiler })
+	if !ok || container.Profiler() == nil {
+		abortWithError(c, http.StatusNotFound, errors.New("profiler is not enabled"))
+		return
+	}
+	report := container.Profiler().Report()
+	switch c.DefaultQuery("format", "json") {
+	case "text":
+		c.String(http.StatusOK, report.String())
+	case "json":
+		toEntries := func(profiles []compcont.ComponentProfile) (entries []profileEntry) {
+			entries = []profileEntry{}
+			for _, profile := range profiles {
+				entry := profileEntry{
+					Path:   profile.String(),
+					TypeID: profile.TypeID,
+					Begin:  profile.Begin,
+					Decode: profile.Decode,
+					Create: profile.Create,
+					Start:  profile.Start,
+					Total:  profile.Total(),
+				}
+				if profile.Err != nil {
+					entry.Error = profile.Err.Error()
+				}
+				entries = append(entries, entry)
+			}
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{
+			"total":         report.Total,
+			"critical_path": toEntries(report.CriticalPath),
+			"self":          report.Self,
+			"components":    toEntries(report.Components),
+		})
+	default:
+		abortWithError(c, http.StatusBadRequest, fmt.Errorf("unsupported format %s", c.Query("format")))
+	}
+}
+
+func (a *Admin) reload(c *gin.Context) {
+	container, err := containerAt(a.root, splitPath(c.Param("path")))
+	if err != nil {
+		abortWithError(c, http.StatusNotFound, err)
+		return
+	}
+	source, ok := container.(compcont.ISourceContainer)
+	if !ok {
+		abortWithError(c, http.StatusNotImplemented, fmt.Errorf("container %T does not support reloading from its source", container))
+		return
+	}
+	if err = source.ReloadSource(c.Request.Context()); err != nil {
+		abortWithError(c, http.StatusInternalServerError, err)
+		return
+	}
+	if snapshot, ok := container.(compcont.ISnapshotContainer); ok {
+		c.JSON(http.StatusOK, snapshot.Snapshot())
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func (a *Admin) rebuild(c *gin.Context) {
+	path := splitPath(c.Param("path"))
+	if len(path) == 0 {
+		abortWithError(c, http.StatusBadRequest, errors.New("component path is required"))
+		return
+	}
+	container, err := containerAt(a.root, path[:len(path)-1])
+	if err != nil {
+		abortWithError(c, http.StatusNotFound, err)
+		return
+	}
+	rebuildable, ok := container.(compcont.IRebuildableContainer)
+	if !ok {
+		abortWithError(c, http.StatusNotImplemented, fmt.Errorf("container %T does not support rebuilding components", container))
+		return
+	}
+	err = rebuildable.RebuildComponents([]compcont.ComponentName{path[len(path)-1]})
+	if errors.Is(err, compcont.ErrComponentNameNotFound) {
+		abortWithError(c, http.StatusNotFound, err)
+		return
+	} else if err != nil {
+		abortWithError(c, http.StatusInternalServerError, err)
+		return
+	}
+	snapshot, _ := findSnapshot(a.snapshot.Snapshot(), path)
+	c.JSON(http.StatusOK, snapshot)
+}
+
+func splitPath(path string) (names []compcont.ComponentName) {
+	for _, name := range strings.Split(path, "/") {
+		if name != "" {
+			names = append(names, compcont.ComponentName(name))
+		}
+	}
+	return
+}
+
+func formatPath(path []compcont.ComponentName) string {
+	var b strings.Builder
+	for _, name := range path {
+		b.WriteString("/")
+		b.WriteString(string(name))
+	}
+	return b.String()
+}
+
+// 在快照树中按绝对路径查找组件
+func findSnapshot(snapshot compcont.ContainerSnapshot, path []compcont.ComponentName) (component compcont.ComponentSnapshot, ok bool) {
+	if len(path) == 0 {
+		return
+	}
+	for _, component = range snapshot.Components {
+		if component.Path[len(component.Path)-1] != path[0] {
+			continue
+		}
+		if len(path) == 1 {
+			return component, true
+		}
+		if component.Container == nil {
+			break
+		}
+		return findSnapshot(*component.Container, path[1:])
+	}
+	return compcont.ComponentSnapshot{}, false
+}
+
+// 按绝对路径逐级获取子容器
+func containerAt(root compcont.IComponentContainer, path []compcont.ComponentName) (container compcont.IComponentContainer, err error) {
+	container = root
+	for _, name := range path {
+		component, err1 := container.GetComponent(name)
+		if err1 != nil {
+			err = err1
+			return
+		}
+		child, ok := component.Instance.(compcont.IComponentContainer)
+		if !ok {
+			err = fmt.Errorf("%w, component %s is not a container", compcont.ErrComponentTypeMismatch, name)
+			return
+		}
+		container = child
+	}
+	return
+}
+
+var factory compcont.IComponentFactory = &compcont.TypedSimpleComponentFactory[Config, *Admin]{
+	TypeID: TypeID,
+	CreateInstanceFunc: func(ctx compcont.Context, config Config) (instance *Admin, err error) {
+		return New(ctx, config)
+	},
+	DestroyInstanceFunc: func(ctx compcont.Context, instance *Admin) (err error) {
+		instance.Close()
+		return
+	},
+}
+
+func Register(registry compcont.IFactoryRegistry) error {
+	return compcont.Register(registry, factory)
+}
+
+func MustRegister(registry compcont.IFactoryRegistry) {
+	compcont.MustRegister(registry, factory)
+}
+
+func init() {
+	compcont.AutoRegister(MustRegister)
+}
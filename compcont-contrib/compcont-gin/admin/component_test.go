@@ -0,0 +1,158 @@
+package admin
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-compcont/compcont/compcont"
+	compcontjwt "github.com/go-compcont/compcont/compcont-contrib/compcont-jwt"
+	"github.com/go-compcont/compcont/compcont-std/container"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAdmin(t *testing.T) {
+	r := compcont.NewFactoryRegistry()
+	MustRegister(r)
+	compcontjwt.MustRegister(r)
+	compcont.MustRegister(r, &compcont.TypedSimpleComponentFactory[map[string]any, *map[string]any]{
+		TypeID: "test",
+		CreateInstanceFunc: func(ctx compcont.Context, config map[string]any) (instance *map[string]any, err error) {
+			if config["fail"] == true {
+				err = errors.New("create failed")
+			}
+			return &config, err
+		},
+	})
+	container.MustRegisterContainerImport(r)
+	file := filepath.Join(t.TempDir(), "imported.yaml")
+	assert.NoError(t, os.WriteFile(file, []byte(`[{name: c, type: test, config: {v: 1}}]`), 0o644))
+
+	engine := gin.New()
+	cc := compcont.NewComponentContainer(compcont.WithFactoryRegistry(r), compcont.WithProfiler(compcont.NewProfiler()))
+	assert.NoError(t, cc.PutComponent("gin", compcont.Component{Instance: gin.IRouter(engine)}))
+	configs := []compcont.ComponentConfig{
+		{Name: "db", Type: "test", Config: map[string]any{"password": "p"}},
+		{Name: "svc", Type: "test", Deps: []compcont.ComponentName{"db"}},
+		{Name: "imported", Type: container.ContainerImportType, Config: map[string]any{"from_file": file}},
+		{Name: "admin", Type: TypeID, Config: map[string]any{
+			"gin": "gin",
+			"jwt": map[string]any{"refer": "jwt"},
+		}},
+	}
+	assert.NoError(t, cc.LoadNamedComponents(append(configs, compcont.ComponentConfig{
+		Name: "jwt", Type: compcontjwt.TypeID, Config: map[string]any{"secret_key": "k"},
+	})))
+	auther, err := compcont.GetComponent[compcontjwt.JWTAuther](cc, "jwt")
+	assert.NoError(t, err)
+	token, err := auther.Instance.Generate(map[string]any{"sub": "admin"})
+	assert.NoError(t, err)
+
+	do := func(method, path, body string, authorized bool) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, DefaultRoutePrefix+path, strings.NewReader(body))
+		if authorized {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		w := httptest.NewRecorder()
+		engine.ServeHTTP(w, req)
+		return w
+	}
+
+	assert.Equal(t, http.StatusUnauthorized, do("GET", "/tree", "", false).Code)
+
+	w := do("GET", "/components/db", "", true)
+	assert.Equal(t, http.StatusOK, w.Code)
+	var snapshot compcont.ComponentSnapshot
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &snapshot))
+	assert.Equal(t, map[string]any{"password": compcont.RedactedValue}, snapshot.Config)
+	assert.Equal(t, http.StatusNotFound, do("GET", "/components/missing", "", true).Code)
+
+	w = do("GET", "/health", "", true)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"status":"ok"`)
+
+	// 创建失败的组件被卸载后恢复健康
+	assert.Error(t, cc.LoadNamedComponents([]compcont.ComponentConfig{{Name: "bad", Type: "test", Config: map[string]any{"fail": true}}}))
+	w = do("GET", "/health", "", true)
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.Contains(t, w.Body.String(), "create failed")
+	assert.NoError(t, cc.UnloadNamedComponents([]compcont.ComponentName{"bad"}, false))
+	assert.Equal(t, http.StatusOK, do("GET", "/health", "", true).Code)
+
+	w = do("GET", "/graph?format=dot", "", true)
+	assert.Contains(t, w.Body.String(), `"/svc" -> "/db";`)
+	assert.Equal(t, http.StatusOK, do("GET", "/profile?format=text", "", true).Code)
+
+	// 重建db时依赖db的svc一同重建
+	svc, err := compcont.GetComponent[*map[string]any](cc, "svc")
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, do("POST", "/rebuild/db", "", true).Code)
+	rebuilt, err := compcont.GetComponent[*map[string]any](cc, "svc")
+	assert.NoError(t, err)
+	assert.NotSame(t, svc.Instance, rebuilt.Instance)
+	assert.Equal(t, http.StatusNotFound, do("POST", "/rebuild/missing", "", true).Code)
+
+	// 重新读取导入的配置文件并热重载子容器，根容器没有配置来源
+	assert.NoError(t, os.WriteFile(file, []byte(`[{name: c, type: test, config: {v: 2}}]`), 0o644))
+	w = do("POST", "/reload/imported", "", true)
+	assert.Equal(t, http.StatusOK, w.Code)
+	imported, err := compcont.GetComponent[compcont.IComponentContainer](cc, "imported")
+	assert.NoError(t, err)
+	component, err := compcont.GetComponent[*map[string]any](imported.Instance, "c")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, (*component.Instance)["v"])
+	assert.Equal(t, http.StatusNotImplemented, do("POST", "/reload/", "", true).Code)
+
+	// 重建jwt时admin随之重建，不会重复注册路由，旧的token仍然有效
+	rebuildable := cc.(compcont.IRebuildableContainer)
+	assert.NotPanics(t, func() {
+		assert.NoError(t, rebuildable.RebuildComponents([]compcont.ComponentName{"jwt"}))
+	})
+	assert.Equal(t, http.StatusOK, do("GET", "/tree", "", true).Code)
+
+	// 修改jwt的密钥后旧的token失效
+	assert.NoError(t, cc.(compcont.IReloadableContainer).ReloadNamedComponents(append(configs, compcont.ComponentConfig{
+		Name: "jwt", Type: compcontjwt.TypeID, Config: map[string]any{"secret_key": "k2"},
+	})))
+	assert.Equal(t, http.StatusUnauthorized, do("GET", "/tree", "", true).Code)
+
+	// 卸载admin后接口不可用，不再保留挂载记录，重新加载时复用已注册的路由
+	assert.NoError(t, cc.UnloadNamedComponents([]compcont.ComponentName{"admin"}, false))
+	assert.Equal(t, http.StatusServiceUnavailable, do("GET", "/tree", "", true).Code)
+	assert.NotContains(t, mounts, mountKey{router: engine, prefix: DefaultRoutePrefix})
+	assert.NoError(t, cc.LoadNamedComponents(configs[len(configs)-1:]))
+	assert.Len(t, mounts[mountKey{router: engine, prefix: DefaultRoutePrefix}], 1)
+	assert.Equal(t, http.StatusUnauthorized, do("GET", "/tree", "", true).Code)
+}
+
+func TestAdminInsecure(t *testing.T) {
+	r := compcont.NewFactoryRegistry()
+	MustRegister(r)
+	engine := gin.New()
+	do := func(method, path string) int {
+		w := httptest.NewRecorder()
+		engine.ServeHTTP(w, httptest.NewRequest(method, path, nil))
+		return w.Code
+	}
+	cc := compcont.NewComponentContainer(compcont.WithFactoryRegistry(r))
+	assert.NoError(t, cc.PutComponent("gin", compcont.Component{Instance: gin.IRouter(engine)}))
+
+	// 未设置jwt时需要显式设置insecure
+	err := cc.LoadNamedComponents([]compcont.ComponentConfig{
+		{Name: "admin", Type: TypeID, Config: map[string]any{"gin": "gin"}},
+	})
+	assert.ErrorIs(t, err, compcont.ErrComponentConfigInvalid)
+	assert.Equal(t, http.StatusNotFound, do("GET", DefaultRoutePrefix+"/tree"))
+
+	assert.NoError(t, cc.LoadNamedComponents([]compcont.ComponentConfig{
+		{Name: "admin", Type: TypeID, Config: map[string]any{"gin": "gin", "insecure": true}},
+	}))
+	assert.Equal(t, http.StatusOK, do("GET", DefaultRoutePrefix+"/tree"))
+	assert.Equal(t, http.StatusNotImplemented, do("POST", DefaultRoutePrefix+"/reload/"))
+}
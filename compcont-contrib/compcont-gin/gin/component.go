@@ -1,34 +1,117 @@
 package compcontgin
 
 import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
 	"github.com/gin-gonic/gin"
 	"github.com/go-compcont/compcont/compcont"
 )
 
 type Config struct {
-	Mode        string                                                `ccf:"mode"`
-	ListenAddrs []string                                              `ccf:"listen_addrs"`
-	Middlewares []compcont.TypedComponentConfig[any, gin.HandlerFunc] `ccf:"middlewares"`
+	Mode               string                                                `ccf:"mode"`
+	ListenAddrs        []string                                              `ccf:"listen_addrs"`
+	Middlewares        []compcont.TypedComponentConfig[any, gin.HandlerFunc] `ccf:"middlewares"`
+	CollectMiddlewares compcont.Collection[gin.HandlerFunc]                  `ccf:"collect_middlewares"` // 收集容器中已加载的中间件，追加在middlewares之后，可以通过selector按标签收集，如 role=middleware, server=public
 }
 
 type Component interface {
 	gin.IRouter
 }
 
+// gin的mode是进程全局的，只在与当前mode不同时设置，避免每次创建组件都写入全局状态
+var modeMu sync.Mutex
+
+func setMode(mode string) {
+	modeMu.Lock()
+	defer modeMu.Unlock()
+	if mode != "" && mode != gin.Mode() {
+		gin.SetMode(mode)
+	}
+}
+
+// 每个gin实例启动的HTTP服务，组件销毁时关闭
+var (
+	serversMu sync.Mutex
+	servers   = map[*gin.Engine][]*http.Server{}
+)
+
+// 在listen_addrs上启动HTTP服务，未设置时与 gin.Engine.Run 一致使用环境变量PORT或 :8080
+func serve(g *gin.Engine, addrs []string) (err error) {
+	if len(addrs) == 0 {
+		addrs = []string{":8080"}
+		if port := os.Getenv("PORT"); port != "" {
+			addrs = []string{":" + port}
+		}
+	}
+	var started []*http.Server
+	for _, addr := range addrs {
+		ln, err1 := net.Listen("tcp", addr)
+		if err1 != nil {
+			err = err1
+			for _, srv := range started {
+				err = errors.Join(err, srv.Close())
+			}
+			return
+		}
+		srv := &http.Server{Handler: g.Handler()}
+		started = append(started, srv)
+		go srv.Serve(ln)
+	}
+	serversMu.Lock()
+	servers[g] = started
+	serversMu.Unlock()
+	return
+}
+
+// 关闭gin实例启动的HTTP服务
+func shutdown(ctx context.Context, g *gin.Engine) (err error) {
+	serversMu.Lock()
+	started := servers[g]
+	delete(servers, g)
+	serversMu.Unlock()
+	for _, srv := range started {
+		err = errors.Join(err, srv.Shutdown(ctx))
+	}
+	return
+}
+
+// New 在容器cc中创建gin组件，中间件等引用相对于cc解析
 func New(cc compcont.IComponentContainer, cfg Config) (c Component, err error) {
-	gin.SetMode(cfg.Mode)
+	return NewWithContext(compcont.Context{Container: cc}, cfg)
+}
+
+// NewWithContext 以组件的上下文创建gin组件，通过Collection收集的中间件会记录为该组件的依赖
+func NewWithContext(ctx compcont.Context, cfg Config) (c Component, err error) {
+	setMode(cfg.Mode)
 	g := gin.New(func(e *gin.Engine) { e.ContextWithFallback = true })
 	var middlewares []gin.HandlerFunc
 	for _, middlewareCfg := range cfg.Middlewares {
-		if component, err1 := middlewareCfg.LoadComponent(cc); err1 != nil {
+		if component, err1 := middlewareCfg.LoadComponent(ctx.Container); errors.Is(err1, compcont.ErrComponentDisabled) {
+			continue
+		} else if err1 != nil {
 			err = err1
 			return
 		} else {
 			middlewares = append(middlewares, component.Instance)
 		}
 	}
+	collected, err := cfg.CollectMiddlewares.Load(ctx)
+	if err != nil {
+		return
+	}
+	for _, component := range collected {
+		middlewares = append(middlewares, component.Instance)
+	}
 	g.Use(middlewares...)
-	go g.Run(cfg.ListenAddrs...)
+	if err = serve(g, cfg.ListenAddrs); err != nil {
+		return
+	}
 	c = g
 	return
 }
@@ -38,14 +121,29 @@ const TypeID compcont.ComponentTypeID = "contrib.gin"
 var factory compcont.IComponentFactory = &compcont.TypedSimpleComponentFactory[Config, Component]{
 	TypeID: TypeID,
 	CreateInstanceFunc: func(ctx compcont.Context, config Config) (instance Component, err error) {
-		return New(ctx.Container, config)
+		return NewWithContext(ctx, config)
+	},
+	DestroyInstanceFunc: func(ctx compcont.Context, instance Component) (err error) {
+		if g, ok := instance.(*gin.Engine); ok {
+			shutCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+			defer cancel()
+			err = shutdown(shutCtx, g)
+		}
+		return
 	},
 }
 
+// 销毁组件时等待请求处理完成的最长时间
+const shutdownTimeout = 10 * time.Second
+
+func Register(registry compcont.IFactoryRegistry) error {
+	return compcont.Register(registry, factory)
+}
+
 func MustRegister(registry compcont.IFactoryRegistry) {
 	compcont.MustRegister(registry, factory)
 }
 
 func init() {
-	MustRegister(compcont.DefaultFactoryRegistry)
+	compcont.AutoRegister(MustRegister)
 }
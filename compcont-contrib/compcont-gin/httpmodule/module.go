@@ -0,0 +1,68 @@
+package httpmodule
+
+import (
+	"github.com/go-compcont/compcont/compcont"
+	compcontgin "github.com/go-compcont/compcont/compcont-contrib/compcont-gin/gin"
+	"github.com/go-compcont/compcont/compcont-contrib/compcont-gin/middleware/prometheus"
+	"github.com/go-compcont/compcont/compcont-contrib/compcont-gin/middleware/recovery"
+	"github.com/go-compcont/compcont/compcont-contrib/compcont-gin/middleware/zap"
+)
+
+const Name = "contrib.http"
+
+// Module 预先配置好recovery、zap、prometheus中间件的gin服务，默认监听 :8080，各组件可以按名称覆盖
+//
+//	# 以子容器加载模块，并覆盖gin组件的监听地址
+//	- name: http
+//	  type: std.module
+//	  config:
+//	    module: contrib.http
+//	    components:
+//	      - { name: gin, config: { listen_addrs: [":9090"] } }
+//
+// prometheus_registry 默认为每个模块实例创建独立的registry，多次加载模块时指标不会重复注册，
+// 需要使用 prometheus.DefaultRegisterer 时覆盖为 { name: prometheus_registry, config: { new: false } }
+var Module = &compcont.Module{
+	Name:      Name,
+	Factories: compcont.FactoriesOf(registers...),
+	Aliases:   compcont.AliasesOf(registers...),
+	Components: []compcont.ComponentConfig{
+		{Name: "prometheus_registry", Type: prometheus.RegistryTypeID, Config: map[string]any{"new": true}},
+		{Name: "recovery", Type: recovery.TypeID},
+		{Name: "zap", Type: zap.TypeID},
+		{
+			Name: "prometheus",
+			Type: prometheus.TypeID,
+			Deps: []compcont.ComponentName{"prometheus_registry"},
+			Config: map[string]any{
+				"namespace": "http",
+				"registry":  map[string]any{"refer": "prometheus_registry"},
+			},
+		},
+		{
+			Name: "gin",
+			Type: compcontgin.TypeID,
+			Deps: []compcont.ComponentName{"recovery", "zap", "prometheus"},
+			Config: map[string]any{
+				"mode":         "release",
+				"listen_addrs": []any{":8080"},
+				"middlewares": []any{
+					map[string]any{"refer": "recovery"},
+					map[string]any{"refer": "zap"},
+					map[string]any{"refer": "prometheus"},
+				},
+			},
+		},
+	},
+}
+
+var registers = []func(registry compcont.IFactoryRegistry){
+	compcontgin.MustRegister,
+	recovery.MustRegister,
+	zap.MustRegister,
+	prometheus.MustRegister,
+}
+
+func init() {
+	compcont.AutoRegisterModule(Module)
+}
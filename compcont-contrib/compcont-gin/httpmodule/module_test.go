@@ -0,0 +1,69 @@
+package httpmodule
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-compcont/compcont/compcont"
+	compcontgin "github.com/go-compcont/compcont/compcont-contrib/compcont-gin/gin"
+	"github.com/go-compcont/compcont/compcont-contrib/compcont-gin/middleware/zap"
+	"github.com/go-compcont/compcont/compcont-std/container"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+)
+
+func moduleConfig(name compcont.ComponentName, components ...any) compcont.ComponentConfig {
+	components = append(components, map[string]any{"name": "gin", "config": map[string]any{"listen_addrs": []any{"127.0.0.1:0"}}})
+	return compcont.ComponentConfig{
+		Name:   name,
+		Type:   container.ModuleContainerType,
+		Config: map[string]any{"module": Name, "components": components},
+	}
+}
+
+func TestModule(t *testing.T) {
+	if _, err := compcont.GetModule(Name); err != nil {
+		compcont.MustRegisterModule(Module)
+	}
+	registry := compcont.NewFactoryRegistry()
+	container.MustRegisterModuleContainer(registry)
+	cc := compcont.NewComponentContainer(compcont.WithFactoryRegistry(registry))
+	t.Cleanup(func() {
+		assert.NoError(t, cc.UnloadNamedComponents(cc.LoadedComponentNames(), true))
+	})
+
+	// 同一个模块可以加载多次，改名前的类型仍然可以在模块中使用
+	assert.NoError(t, cc.LoadNamedComponents([]compcont.ComponentConfig{
+		moduleConfig("public"),
+		moduleConfig("internal", map[string]any{"name": "zap", "type": string(zap.DeprecatedTypeID)}),
+	}))
+
+	var registries []prometheus.Registerer
+	for _, name := range []compcont.ComponentName{"public", "internal"} {
+		child, err := compcont.GetComponent[compcont.IComponentContainer](cc, name)
+		assert.NoError(t, err)
+		g, err := compcont.GetComponent[compcontgin.Component](child.Instance, "gin")
+		assert.NoError(t, err)
+		g.Instance.GET("/ping", func(c *gin.Context) { c.String(http.StatusOK, "pong") })
+		w := httptest.NewRecorder()
+		g.Instance.(*gin.Engine).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/ping", nil))
+		assert.Equal(t, "pong", w.Body.String())
+
+		// 请求指标记录在模块实例各自的registry中
+		r, err := compcont.GetComponent[prometheus.Registerer](child.Instance, "prometheus_registry")
+		assert.NoError(t, err)
+		registries = append(registries, r.Instance)
+		families, err := r.Instance.(*prometheus.Registry).Gather()
+		assert.NoError(t, err)
+		var count float64
+		for _, family := range families {
+			if family.GetName() == "http_http_request_count_total" {
+				count = family.GetMetric()[0].GetCounter().GetValue()
+			}
+		}
+		assert.Equal(t, float64(1), count)
+	}
+	assert.NotSame(t, registries[0], registries[1])
+}
@@ -134,10 +134,14 @@ var factory compcont.IComponentFactory = &compcont.TypedSimpleComponentFactory[C
 	},
 }
 
+func Register(registry compcont.IFactoryRegistry) error {
+	return compcont.Register(registry, factory, containerEventsFactory, registryFactory)
+}
+
 func MustRegister(registry compcont.IFactoryRegistry) {
-	compcont.MustRegister(registry, factory)
+	compcont.MustRegister(registry, factory, containerEventsFactory, registryFactory)
 }
 
 func init() {
-	MustRegister(compcont.DefaultFactoryRegistry)
+	compcont.AutoRegister(MustRegister)
 }
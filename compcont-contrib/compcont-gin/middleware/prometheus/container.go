@@ -0,0 +1,111 @@
+package prometheus
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/go-compcont/compcont/compcont"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type ContainerEventsConfig struct {
+	Namespace string                                                    `ccf:"namespace"`
+	Registry  compcont.TypedComponentConfig[any, prometheus.Registerer] `ccf:"registry"`
+}
+
+// ContainerEvents 将所在容器及其子孙容器的生命周期事件转换为prometheus指标
+type ContainerEvents struct {
+	container  compcont.IEventContainer
+	registry   prometheus.Registerer
+	listenerID int
+	events     *prometheus.CounterVec
+	durations  *prometheus.HistogramVec
+}
+
+func (e *ContainerEvents) OnEvent(event compcont.Event) {
+	switch event.Type {
+	case compcont.EventContainerLoading, compcont.EventContainerLoaded, compcont.EventContainerReloading, compcont.EventContainerReloaded:
+	default:
+		if event.TypeID == "" {
+			// 引用其他组件得到的组件没有类型，不计入指标
+			return
+		}
+	}
+	status := "ok"
+	if event.Err != nil {
+		status = "error"
+	}
+	e.events.WithLabelValues(string(event.Type), string(event.TypeID), status).Inc()
+	switch event.Type {
+	case compcont.EventComponentLoaded, compcont.EventComponentFailed, compcont.EventComponentUnloaded, compcont.EventContainerLoaded, compcont.EventContainerReloaded:
+		e.durations.WithLabelValues(string(event.Type), string(event.TypeID), status).Observe(event.Duration.Seconds())
+	}
+}
+
+// Close 停止监听容器事件并注销指标
+func (e *ContainerEvents) Close() {
+	e.container.RemoveEventListener(e.listenerID)
+	e.registry.Unregister(e.events)
+	e.registry.Unregister(e.durations)
+}
+
+func NewContainerEvents(cc compcont.IComponentContainer, cfg ContainerEventsConfig) (c *ContainerEvents, err error) {
+	container, ok := compcont.UnwrapContainer[compcont.IEventContainer](cc)
+	if !ok {
+		err = fmt.Errorf("%w, container %T does not support event listeners", compcont.ErrComponentTypeMismatch, cc)
+		return
+	}
+	regComp, err := cfg.Registry.LoadComponent(cc)
+	if err != nil {
+		return
+	}
+	namespace := cfg.Namespace
+	if namespace == "" {
+		err = errors.New("namespace must be set")
+		return
+	}
+
+	labels := []string{"event", "type", "status"}
+	c = &ContainerEvents{
+		container: container,
+		registry:  regComp.Instance,
+		events: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Name:      "container_events_total",
+				Help:      "Total number of component container lifecycle events.",
+			}, labels,
+		),
+		durations: prometheus.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Namespace: namespace,
+				Name:      "container_event_duration_seconds",
+				Help:      "Component load, unload and container reload latencies in seconds.",
+			}, labels,
+		),
+	}
+	err = c.registry.Register(c.events)
+	if err != nil {
+		return
+	}
+	err = c.registry.Register(c.durations)
+	if err != nil {
+		c.registry.Unregister(c.events)
+		return
+	}
+	c.listenerID = container.AddEventListener(c)
+	return
+}
+
+const ContainerEventsTypeID compcont.ComponentTypeID = "contrib.prometheus-container-events"
+
+var containerEventsFactory compcont.IComponentFactory = &compcont.TypedSimpleComponentFactory[ContainerEventsConfig, *ContainerEvents]{
+	TypeID: ContainerEventsTypeID,
+	CreateInstanceFunc: func(ctx compcont.Context, config ContainerEventsConfig) (instance *ContainerEvents, err error) {
+		return NewContainerEvents(ctx.Container, config)
+	},
+	DestroyInstanceFunc: func(ctx compcont.Context, instance *ContainerEvents) (err error) {
+		instance.Close()
+		return
+	},
+}
@@ -0,0 +1,22 @@
+package prometheus
+
+import (
+	"github.com/go-compcont/compcont/compcont"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type RegistryConfig struct {
+	New bool `ccf:"new"` // 为true时创建独立的registry，否则使用 prometheus.DefaultRegisterer
+}
+
+const RegistryTypeID compcont.ComponentTypeID = "contrib.prometheus-registry"
+
+var registryFactory compcont.IComponentFactory = &compcont.TypedSimpleComponentFactory[RegistryConfig, prometheus.Registerer]{
+	TypeID: RegistryTypeID,
+	CreateInstanceFunc: func(ctx compcont.Context, config RegistryConfig) (instance prometheus.Registerer, err error) {
+		if config.New {
+			return prometheus.NewRegistry(), nil
+		}
+		return prometheus.DefaultRegisterer, nil
+	},
+}
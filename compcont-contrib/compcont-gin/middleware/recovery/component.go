@@ -17,10 +17,14 @@ var factory compcont.IComponentFactory = &compcont.TypedSimpleComponentFactory[C
 	},
 }
 
+func Register(registry compcont.IFactoryRegistry) error {
+	return compcont.Register(registry, factory)
+}
+
 func MustRegister(registry compcont.IFactoryRegistry) {
 	compcont.MustRegister(registry, factory)
 }
 
 func init() {
-	MustRegister(compcont.DefaultFactoryRegistry)
+	compcont.AutoRegister(MustRegister)
 }
@@ -116,7 +116,12 @@ func New(cc compcont.IComponentContainer, cfg Config) (c gin.HandlerFunc, err er
 	return
 }
 
-const TypeID compcont.ComponentTypeID = "base.gin-middleware-zap"
+const TypeID compcont.ComponentTypeID = "contrib.gin-middleware-zap"
+
+// 改名前的类型，保留为废弃的别名以兼容已有的配置
+const DeprecatedTypeID compcont.ComponentTypeID = "base.gin-middleware-zap"
+
+var deprecatedAlias = compcont.TypeAlias{Alias: DeprecatedTypeID, Target: TypeID, Deprecated: true}
 
 var factory compcont.IComponentFactory = &compcont.TypedSimpleComponentFactory[Config, gin.HandlerFunc]{
 	TypeID: TypeID,
@@ -125,10 +130,22 @@ var factory compcont.IComponentFactory = &compcont.TypedSimpleComponentFactory[C
 	},
 }
 
+func Register(registry compcont.IFactoryRegistry) (err error) {
+	if err = compcont.Register(registry, factory); err != nil {
+		return
+	}
+	if err = compcont.RegisterAlias(registry, deprecatedAlias); err != nil {
+		registry.Unregister(TypeID)
+	}
+	return
+}
+
 func MustRegister(registry compcont.IFactoryRegistry) {
-	compcont.MustRegister(registry, factory)
+	if err := Register(registry); err != nil {
+		panic(err)
+	}
 }
 
 func init() {
-	MustRegister(compcont.DefaultFactoryRegistry)
+	compcont.AutoRegister(MustRegister)
 }
@@ -7,14 +7,20 @@ import (
 )
 
 type Config struct {
-	Gin         compcont.TypedComponentConfig[any, gin.IRouter] `ccf:"gin"`
-	RoutePrefix string                                          `ccf:"route_prefix"`
+	Gin         compcont.Ref[gin.IRouter] `ccf:"gin"` // 挂载pprof路由的gin组件
+	RoutePrefix string                    `ccf:"route_prefix"`
 }
 
 const TypeID compcont.ComponentTypeID = "contrib.gin-pprof"
 
+// New 将pprof路由挂载到容器cc中的gin组件上
 func New(cc compcont.IComponentContainer, cfg Config) (err error) {
-	g, err := cfg.Gin.LoadComponent(cc)
+	return NewWithContext(compcont.Context{Container: cc}, cfg)
+}
+
+// NewWithContext 以组件的上下文挂载pprof路由，引用的gin组件会记录为该组件的依赖
+func NewWithContext(ctx compcont.Context, cfg Config) (err error) {
+	g, err := cfg.Gin.Load(ctx)
 	if err != nil {
 		return
 	}
@@ -29,15 +35,19 @@ func New(cc compcont.IComponentContainer, cfg Config) (err error) {
 var factory compcont.IComponentFactory = &compcont.TypedSimpleComponentFactory[Config, any]{
 	TypeID: TypeID,
 	CreateInstanceFunc: func(ctx compcont.Context, config Config) (instance any, err error) {
-		err = New(ctx.Container, config)
+		err = NewWithContext(ctx, config)
 		return
 	},
 }
 
+func Register(registry compcont.IFactoryRegistry) error {
+	return compcont.Register(registry, factory)
+}
+
 func MustRegister(registry compcont.IFactoryRegistry) {
 	compcont.MustRegister(registry, factory)
 }
 
 func init() {
-	MustRegister(compcont.DefaultFactoryRegistry)
+	compcont.AutoRegister(MustRegister)
 }
@@ -0,0 +1,314 @@
+package compcontotel
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/go-compcont/compcont/compcont"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+const TypeID compcont.ComponentTypeID = "contrib.otel-tracer-provider"
+
+type Config struct {
+	ServiceName     string `ccf:"service_name"`
+	Exporter        string `ccf:"exporter"`          // "stdout","none"，默认为stdout
+	PrettyPrint     bool   `ccf:"pretty_print"`      // stdout导出器是否格式化输出
+	SetGlobal       bool   `ccf:"set_global"`        // 是否设置为全局的TracerProvider，关闭时恢复之前的全局TracerProvider
+	TraceContainer  bool   `ccf:"trace_container"`   // 是否追踪所在容器及其子孙容器的加载、卸载和热重载，该组件加载之前已加载的组件按记录的加载耗时补充span
+	SampleAllTraces *bool  `ccf:"sample_all_traces"` // 是否采样全部trace，默认为true，为false时跟随父span的采样决定
+}
+
+type Component interface {
+	trace.TracerProvider
+	Shutdown(ctx context.Context) error
+}
+
+type component struct {
+	*sdktrace.TracerProvider
+	container  compcont.IEventContainer
+	listenerID int
+}
+
+func (c *component) Shutdown(ctx context.Context) error {
+	if c.container != nil {
+		c.container.RemoveEventListener(c.listenerID)
+	}
+	unsetGlobal(c)
+	return c.TracerProvider.Shutdown(ctx)
+}
+
+// 设置为全局TracerProvider的组件，按设置顺序排列
+var globals struct {
+	mu       sync.Mutex
+	previous trace.TracerProvider // 首个组件设置之前的全局TracerProvider
+	stack    []*component
+}
+
+func setGlobal(c *component) {
+	globals.mu.Lock()
+	defer globals.mu.Unlock()
+	if len(globals.stack) == 0 {
+		globals.previous = otel.GetTracerProvider()
+	}
+	globals.stack = append(globals.stack, c)
+	otel.SetTracerProvider(c)
+}
+
+// 关闭的组件仍为全局TracerProvider时，恢复为之前设置的全局TracerProvider
+func unsetGlobal(c *component) {
+	globals.mu.Lock()
+	defer globals.mu.Unlock()
+	i := slices.Index(globals.stack, c)
+	if i < 0 {
+		return
+	}
+	globals.stack = slices.Delete(globals.stack, i, i+1)
+	previous := globals.previous
+	if len(globals.stack) == 0 {
+		globals.previous = nil
+	} else {
+		previous = globals.stack[len(globals.stack)-1]
+	}
+	if i != len(globals.stack) || otel.GetTracerProvider() != trace.TracerProvider(c) {
+		return
+	}
+	// otel默认的全局TracerProvider不能被重新设置，以noop代替
+	if t := reflect.TypeOf(previous); t.Kind() == reflect.Pointer && t.Elem().PkgPath() == "go.opentelemetry.io/otel/internal/global" {
+		previous = noop.NewTracerProvider()
+	}
+	otel.SetTracerProvider(previous)
+}
+
+func New(container compcont.IComponentContainer, cfg Config) (comp Component, err error) {
+	var events compcont.IEventContainer
+	if cfg.TraceContainer && container != nil {
+		var ok bool
+		if events, ok = compcont.UnwrapContainer[compcont.IEventContainer](container); !ok {
+			err = fmt.Errorf("%w, container %T does not support event listeners", compcont.ErrComponentTypeMismatch, container)
+			return
+		}
+	}
+	var opts []sdktrace.TracerProviderOption
+	switch cfg.Exporter {
+	case "", "stdout":
+		stdoutOpts := []stdouttrace.Option{stdouttrace.WithWriter(os.Stdout)}
+		if cfg.PrettyPrint {
+			stdoutOpts = append(stdoutOpts, stdouttrace.WithPrettyPrint())
+		}
+		exporter, err := stdouttrace.New(stdoutOpts...)
+		if err != nil {
+			return nil, err
+		}
+		// 同步导出以便本地调试时立即看到span
+		opts = append(opts, sdktrace.WithSyncer(exporter))
+	case "none":
+	default:
+		err = fmt.Errorf("unknown exporter: %s", cfg.Exporter)
+		return
+	}
+	if cfg.ServiceName != "" {
+		opts = append(opts, sdktrace.WithResource(resource.NewWithAttributes(semconv.SchemaURL, semconv.ServiceName(cfg.ServiceName))))
+	}
+	if cfg.SampleAllTraces == nil || *cfg.SampleAllTraces {
+		opts = append(opts, sdktrace.WithSampler(sdktrace.AlwaysSample()))
+	}
+
+	c := &component{TracerProvider: sdktrace.NewTracerProvider(opts...)}
+	if cfg.SetGlobal {
+		setGlobal(c)
+	}
+	if events != nil {
+		c.container = events
+		c.listenerID = events.AddEventListener(NewEventListener(c))
+		if snapshotter, ok := compcont.UnwrapContainer[compcont.ISnapshotContainer](container); ok {
+			traceLoaded(c.Tracer(tracerName), context.Background(), snapshotter.Snapshot())
+		}
+	}
+	comp = c
+	return
+}
+
+const tracerName = "github.com/go-compcont/compcont"
+
+// 为已加载完成的组件补充span，子容器中的组件为子容器组件span的子span
+func traceLoaded(tracer trace.Tracer, parent context.Context, snapshot compcont.ContainerSnapshot) {
+	for _, component := range snapshot.Components {
+		if component.State != compcont.StateReady || component.LoadedAt.IsZero() {
+			continue
+		}
+		attrs := []attribute.KeyValue{
+			attribute.String("compcont.path", formatPath(component.Path)),
+			attribute.Bool("compcont.backfilled", true),
+		}
+		if component.TypeID != "" {
+			attrs = append(attrs, attribute.String("compcont.type_id", string(component.TypeID)))
+		}
+		ctx, span := tracer.Start(parent, spanNames[compcont.EventComponentLoading],
+			trace.WithTimestamp(component.LoadedAt.Add(-component.LoadDuration)),
+			trace.WithAttributes(attrs...),
+		)
+		if component.Container != nil {
+			traceLoaded(tracer, ctx, *component.Container)
+		}
+		span.End(trace.WithTimestamp(component.LoadedAt))
+	}
+}
+
+// 每种操作开始和结束事件的对应关系
+var spanNames = map[compcont.EventType]string{
+	compcont.EventContainerLoading:   "LoadNamedComponents",
+	compcont.EventContainerLoaded:    "LoadNamedComponents",
+	compcont.EventContainerReloading: "ReloadNamedComponents",
+	compcont.EventContainerReloaded:  "ReloadNamedComponents",
+	compcont.EventComponentLoading:   "CreateInstance",
+	compcont.EventComponentLoaded:    "CreateInstance",
+	compcont.EventComponentFailed:    "CreateInstance",
+	compcont.EventComponentUnloading: "DestroyInstance",
+	compcont.EventComponentUnloaded:  "DestroyInstance",
+}
+
+type eventListener struct {
+	tracer trace.Tracer
+	mu     sync.Mutex
+	spans  []openSpan // 尚未结束的span
+}
+
+type openSpan struct {
+	name      string
+	path      string
+	container bool // 是否为容器的加载或热重载
+	ctx       context.Context
+	span      trace.Span
+}
+
+// NewEventListener 创建一个将容器生命周期事件转换为span的监听器
+//
+// span的父子关系按路径确定：组件的创建和销毁是其所在容器正在进行的加载或热重载的子span，
+// 容器的加载是正在创建该容器的组件的子span，均不存在时沿路径向上查找，因此并发进行的操作不会互相嵌套
+func NewEventListener(provider trace.TracerProvider) compcont.EventListener {
+	return &eventListener{tracer: provider.Tracer(tracerName)}
+}
+
+func isContainerEvent(eventType compcont.EventType) bool {
+	switch eventType {
+	case compcont.EventContainerLoading, compcont.EventContainerLoaded, compcont.EventContainerReloading, compcont.EventContainerReloaded:
+		return true
+	default:
+		return false
+	}
+}
+
+func (l *eventListener) OnEvent(event compcont.Event) {
+	name, ok := spanNames[event.Type]
+	if !ok {
+		return
+	}
+	path := formatPath(event.Path)
+	container := isContainerEvent(event.Type)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	switch event.Type {
+	case compcont.EventContainerLoading, compcont.EventContainerReloading, compcont.EventComponentLoading, compcont.EventComponentUnloading:
+		attrs := []attribute.KeyValue{attribute.String("compcont.path", path)}
+		if event.TypeID != "" {
+			attrs = append(attrs, attribute.String("compcont.type_id", string(event.TypeID)))
+		}
+		ctx, span := l.tracer.Start(l.parent(event), name, trace.WithAttributes(attrs...))
+		l.spans = append(l.spans, openSpan{name: name, path: path, container: container, ctx: ctx, span: span})
+	default:
+		// 监听器添加之前开始的操作没有对应的span，忽略
+		for i := len(l.spans) - 1; i >= 0; i-- {
+			if l.spans[i].name != name || l.spans[i].path != path {
+				continue
+			}
+			span := l.spans[i].span
+			if event.Err != nil {
+				span.RecordError(event.Err)
+				span.SetStatus(codes.Error, event.Err.Error())
+			}
+			span.End()
+			l.spans = append(l.spans[:i], l.spans[i+1:]...)
+			return
+		}
+	}
+}
+
+// 按路径查找事件的父span，依次为容器正在进行的加载或热重载、正在创建该容器的组件，再到上一级容器
+func (l *eventListener) parent(event compcont.Event) context.Context {
+	path := event.Path
+	container := true
+	if isContainerEvent(event.Type) {
+		container = false
+	} else if len(path) > 0 {
+		path = path[:len(path)-1]
+	}
+	for {
+		if span, ok := l.latest(formatPath(path), container); ok {
+			return span.ctx
+		}
+		if container {
+			container = false
+			continue
+		}
+		if len(path) == 0 {
+			return context.Background()
+		}
+		path = path[:len(path)-1]
+		container = true
+	}
+}
+
+// 路径对应的最后开始的span
+func (l *eventListener) latest(path string, container bool) (span openSpan, ok bool) {
+	for i := len(l.spans) - 1; i >= 0; i-- {
+		if l.spans[i].path == path && l.spans[i].container == container {
+			return l.spans[i], true
+		}
+	}
+	return
+}
+
+func formatPath(path []compcont.ComponentName) string {
+	names := make([]string, 0, len(path))
+	for _, name := range path {
+		names = append(names, string(name))
+	}
+	return "/" + strings.Join(names, "/")
+}
+
+var factory compcont.IComponentFactory = &compcont.TypedSimpleComponentFactory[Config, Component]{
+	TypeID: TypeID,
+	CreateInstanceFunc: func(ctx compcont.Context, config Config) (instance Component, err error) {
+		return New(ctx.Container, config)
+	},
+	DestroyInstanceFunc: func(ctx compcont.Context, instance Component) (err error) {
+		return instance.Shutdown(context.Background())
+	},
+}
+
+func Register(registry compcont.IFactoryRegistry) error {
+	return compcont.Register(registry, factory)
+}
+
+func MustRegister(registry compcont.IFactoryRegistry) {
+	compcont.MustRegister(registry, factory)
+}
+
+func init() {
+	compcont.AutoRegister(MustRegister)
+}
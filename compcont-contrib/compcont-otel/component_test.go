@@ -0,0 +1,165 @@
+package compcontotel
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/go-compcont/compcont/compcont"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+func TestEventListener(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	r := compcont.NewFactoryRegistry()
+	compcont.MustRegister(r, &compcont.TypedSimpleComponentFactory[string, string]{
+		TypeID: "test",
+		CreateInstanceFunc: func(ctx compcont.Context, config string) (instance string, err error) {
+			if config == "fail" {
+				err = errors.New("create failed")
+			}
+			return config, err
+		},
+	})
+	cc := compcont.NewComponentContainer(compcont.WithFactoryRegistry(r))
+	cc.(compcont.IEventContainer).AddEventListener(NewEventListener(provider))
+	assert.Error(t, cc.LoadNamedComponents([]compcont.ComponentConfig{
+		{Name: "a", Type: "test", Config: "a"},
+		{Name: "b", Type: "test", Deps: []compcont.ComponentName{"a"}, Config: "fail"},
+	}))
+
+	spans := recorder.Ended()
+	assert.Len(t, spans, 3)
+	root := spans[2]
+	assert.Equal(t, "LoadNamedComponents", root.Name())
+	assert.Equal(t, codes.Error, root.Status().Code)
+	for _, span := range spans[:2] {
+		assert.Equal(t, "CreateInstance", span.Name())
+		assert.Equal(t, root.SpanContext().SpanID(), span.Parent().SpanID())
+	}
+	assert.Contains(t, spans[1].Attributes(), attribute.String("compcont.path", "/b"))
+	assert.Equal(t, codes.Error, spans[1].Status().Code)
+}
+
+func TestEventListenerConcurrent(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	listener := NewEventListener(provider)
+
+	// 子容器的热重载与根容器中组件的加载交错进行
+	for _, event := range []compcont.Event{
+		{Type: compcont.EventContainerReloading, Path: []compcont.ComponentName{"c1"}},
+		{Type: compcont.EventComponentLoading, Path: []compcont.ComponentName{"a"}, TypeID: "test"},
+		{Type: compcont.EventComponentLoading, Path: []compcont.ComponentName{"c1", "x"}, TypeID: "test"},
+		{Type: compcont.EventComponentLoading, Path: []compcont.ComponentName{"c1", ""}, TypeID: "test"},
+		{Type: compcont.EventComponentLoaded, Path: []compcont.ComponentName{"a"}, TypeID: "test"},
+		{Type: compcont.EventComponentLoaded, Path: []compcont.ComponentName{"c1", ""}, TypeID: "test"},
+		{Type: compcont.EventComponentLoaded, Path: []compcont.ComponentName{"c1", "x"}, TypeID: "test"},
+		{Type: compcont.EventContainerReloaded, Path: []compcont.ComponentName{"c1"}},
+	} {
+		listener.OnEvent(event)
+	}
+
+	spans := make(map[string]sdktrace.ReadOnlySpan)
+	for _, span := range recorder.Ended() {
+		for _, attr := range span.Attributes() {
+			if attr.Key == "compcont.path" {
+				spans[attr.Value.AsString()] = span
+			}
+		}
+	}
+	assert.Len(t, spans, 4)
+	assert.False(t, spans["/a"].Parent().IsValid())
+	assert.False(t, spans["/c1"].Parent().IsValid())
+	assert.Equal(t, spans["/c1"].SpanContext().SpanID(), spans["/c1/x"].Parent().SpanID())
+	assert.Equal(t, spans["/c1"].SpanContext().SpanID(), spans["/c1/"].Parent().SpanID())
+}
+
+func TestFactory(t *testing.T) {
+	// stdout导出器在创建时绑定os.Stdout
+	stdout := os.Stdout
+	reader, writer, err := os.Pipe()
+	assert.NoError(t, err)
+	os.Stdout = writer
+	defer func() { os.Stdout = stdout }()
+
+	r := compcont.NewFactoryRegistry()
+	MustRegister(r)
+	compcont.MustRegister(r, &compcont.TypedSimpleComponentFactory[string, string]{
+		TypeID: "test",
+		CreateInstanceFunc: func(ctx compcont.Context, config string) (instance string, err error) {
+			return config, nil
+		},
+	})
+	cc := compcont.NewComponentContainer(compcont.WithFactoryRegistry(r))
+	assert.NoError(t, cc.LoadNamedComponents([]compcont.ComponentConfig{
+		{Name: "a", Type: "test", Config: "a"},
+		{Name: "tracer", Type: TypeID, Deps: []compcont.ComponentName{"a"}, Config: map[string]any{"service_name": "test", "trace_container": true}},
+	}))
+	assert.NoError(t, cc.LoadNamedComponents([]compcont.ComponentConfig{{Name: "b", Type: "test", Config: "b"}}))
+	assert.NoError(t, cc.UnloadNamedComponents([]compcont.ComponentName{"tracer"}, false))
+	os.Stdout = stdout
+	assert.NoError(t, writer.Close())
+	output, err := io.ReadAll(reader)
+	assert.NoError(t, err)
+
+	// 先于tracer加载的a补充了span，之后加载的b被实时追踪
+	var paths []string
+	decoder := json.NewDecoder(bytes.NewReader(output))
+	for decoder.More() {
+		var span struct {
+			Name       string
+			Attributes []struct {
+				Key   string
+				Value struct{ Value any }
+			}
+		}
+		assert.NoError(t, decoder.Decode(&span))
+		for _, attr := range span.Attributes {
+			if attr.Key == "compcont.path" {
+				paths = append(paths, span.Name+" "+attr.Value.Value.(string))
+			}
+		}
+	}
+	assert.Equal(t, []string{"CreateInstance /a", "CreateInstance /b", "LoadNamedComponents /"}, paths)
+
+	_, err = cc.LoadAnonymousComponent(compcont.ComponentConfig{Type: TypeID, Config: map[string]any{"exporter": "unknown"}})
+	assert.Error(t, err)
+}
+
+func TestSetGlobal(t *testing.T) {
+	previous := noop.NewTracerProvider()
+	otel.SetTracerProvider(previous)
+
+	r := compcont.NewFactoryRegistry()
+	MustRegister(r)
+	cc := compcont.NewComponentContainer(compcont.WithFactoryRegistry(r))
+	config := compcont.ComponentConfig{Name: "tracer", Type: TypeID, Config: map[string]any{"exporter": "none", "set_global": true}}
+	assert.NoError(t, cc.LoadNamedComponents([]compcont.ComponentConfig{config}))
+	tracer, err := compcont.GetComponent[Component](cc, "tracer")
+	assert.NoError(t, err)
+	assert.Equal(t, trace.TracerProvider(tracer.Instance), otel.GetTracerProvider())
+
+	// 重建时新的组件成为全局TracerProvider，旧的组件关闭时不恢复
+	assert.NoError(t, cc.(compcont.IRebuildableContainer).RebuildComponents([]compcont.ComponentName{"tracer"}))
+	rebuilt, err := compcont.GetComponent[Component](cc, "tracer")
+	assert.NoError(t, err)
+	assert.NotSame(t, tracer.Instance, rebuilt.Instance)
+	assert.Equal(t, trace.TracerProvider(rebuilt.Instance), otel.GetTracerProvider())
+
+	// 卸载后恢复为之前的全局TracerProvider
+	assert.NoError(t, cc.UnloadNamedComponents([]compcont.ComponentName{"tracer"}, false))
+	assert.Equal(t, trace.TracerProvider(previous), otel.GetTracerProvider())
+}
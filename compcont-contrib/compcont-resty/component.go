@@ -1,6 +1,10 @@
 package restyprovider
 
-import "github.com/go-compcont/compcont/compcont"
+import (
+	"errors"
+
+	"github.com/go-compcont/compcont/compcont"
+)
 
 const SimpleTypeID compcont.ComponentTypeID = "contrib.resty-provider-simple"
 
@@ -24,11 +28,19 @@ var ruleFactory compcont.IComponentFactory = &compcont.TypedSimpleComponentFacto
 	},
 }
 
+func Register(registry compcont.IFactoryRegistry) error {
+	return compcont.Register(registry, simpleFactory, ruleFactory)
+}
+
+// MustRegister 注册resty的工厂，已经注册过的类型被忽略，因此可以在自动注册之后再次显式调用
 func MustRegister(registry compcont.IFactoryRegistry) {
-	registry.Register(simpleFactory)
-	registry.Register(ruleFactory)
+	for _, factory := range []compcont.IComponentFactory{simpleFactory, ruleFactory} {
+		if err := compcont.Register(registry, factory); err != nil && !errors.Is(err, compcont.ErrComponentTypeAlreadyRegistered) {
+			panic(err)
+		}
+	}
 }
 
 func init() {
-	MustRegister(compcont.DefaultFactoryRegistry)
+	compcont.AutoRegister(MustRegister)
 }
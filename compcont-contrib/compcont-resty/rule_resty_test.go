@@ -12,7 +12,8 @@ import (
 )
 
 func TestRuleProvider(t *testing.T) {
-	compcont.DefaultFactoryRegistry.Register(&compcont.TypedSimpleComponentFactory[string, RestyProvider]{
+	registry := compcont.CloneFactoryRegistry(compcont.DefaultFactoryRegistry)
+	compcont.MustRegister(registry, &compcont.TypedSimpleComponentFactory[string, RestyProvider]{
 		TypeID: "mock.resty",
 		CreateInstanceFunc: func(ctx compcont.Context, config string) (instance RestyProvider, err error) {
 			comp := GetRestyFunc(func(opts ...OptionsFunc) (*resty.Client, error) {
@@ -23,7 +24,7 @@ func TestRuleProvider(t *testing.T) {
 		},
 	})
 
-	cc := compcont.NewComponentContainer()
+	cc := compcont.NewComponentContainer(compcont.WithFactoryRegistry(registry))
 
 	p, err := newRuleProviderImpl(cc, RuleProviderConfig{
 		DefaultProvider: compcont.TypedComponentConfig[any, RestyProvider]{
@@ -55,3 +56,14 @@ func TestRuleProvider(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Contains(t, cli.BaseURL, "test-default")
 }
+
+func TestMustRegisterTwice(t *testing.T) {
+	registry := compcont.NewFactoryRegistry()
+	// 自动注册之后再次显式注册不会panic
+	assert.NotPanics(t, func() {
+		MustRegister(registry)
+		MustRegister(registry)
+	})
+	_, err := registry.GetFactory(SimpleTypeID)
+	assert.NoError(t, err)
+}
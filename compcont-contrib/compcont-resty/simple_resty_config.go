@@ -59,7 +59,7 @@ type ProxyConfig struct {
 }
 
 type SimpleProviderConfig struct {
-	Once      bool            `ccf:"once"` // 是否单例
+	Once      bool            `ccf:"once"` // 是否复用同一个client，与组件的scope无关，需要按请求隔离client时可将组件声明为scoped
 	Debug     DebugConfig     `ccf:"debug"`
 	Timeout   time.Duration   `ccf:"timeout"`
 	Proxy     ProxyConfig     `ccf:"proxy"`
@@ -10,19 +10,31 @@ const TypeID compcont.ComponentTypeID = "contrib.zap"
 var factory compcont.IComponentFactory = &compcont.TypedSimpleComponentFactory[Config, *zap.Logger]{
 	TypeID: TypeID,
 	CreateInstanceFunc: func(ctx compcont.Context, config Config) (instance *zap.Logger, err error) {
-		logger, err := New(config)
-		if err != nil {
-			return
-		}
-		instance = logger
+		return New(config)
+	},
+}
+
+const ContainerEventsTypeID compcont.ComponentTypeID = "contrib.zap-container-events"
+
+var containerEventsFactory compcont.IComponentFactory = &compcont.TypedSimpleComponentFactory[ContainerEventsConfig, *ContainerEvents]{
+	TypeID: ContainerEventsTypeID,
+	CreateInstanceFunc: func(ctx compcont.Context, config ContainerEventsConfig) (instance *ContainerEvents, err error) {
+		return NewContainerEvents(ctx.Container, config)
+	},
+	DestroyInstanceFunc: func(ctx compcont.Context, instance *ContainerEvents) (err error) {
+		instance.Close()
 		return
 	},
 }
 
+func Register(registry compcont.IFactoryRegistry) error {
+	return compcont.Register(registry, factory, containerEventsFactory)
+}
+
 func MustRegister(registry compcont.IFactoryRegistry) {
-	compcont.MustRegister(registry, factory)
+	compcont.MustRegister(registry, factory, containerEventsFactory)
 }
 
 func init() {
-	MustRegister(compcont.DefaultFactoryRegistry)
+	compcont.AutoRegister(MustRegister)
 }
@@ -0,0 +1,66 @@
+package compcontzap
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/go-compcont/compcont/compcont"
+	"go.uber.org/zap"
+)
+
+// NewEventListener 创建一个将容器生命周期事件输出到logger的监听器，失败事件以Error级别输出，其余为Debug级别
+func NewEventListener(logger *zap.Logger) compcont.EventListener {
+	return compcont.EventListenerFunc(func(event compcont.Event) {
+		path := make([]string, 0, len(event.Path))
+		for _, name := range event.Path {
+			path = append(path, string(name))
+		}
+		fields := []zap.Field{
+			zap.String("event", string(event.Type)),
+			zap.String("path", "/"+strings.Join(path, "/")),
+		}
+		if event.TypeID != "" {
+			fields = append(fields, zap.String("type", string(event.TypeID)))
+		}
+		if event.Duration > 0 {
+			fields = append(fields, zap.Duration("duration", event.Duration))
+		}
+		if event.Err != nil {
+			logger.Error("container event", append(fields, zap.Error(event.Err))...)
+			return
+		}
+		logger.Debug("container event", fields...)
+	})
+}
+
+type ContainerEventsConfig struct {
+	Logger compcont.TypedComponentConfig[any, *zap.Logger] `ccf:"logger"` // 输出事件的logger，通常引用一个 contrib.zap 组件
+}
+
+// ContainerEvents 将所在容器及其子孙容器的生命周期事件输出到logger
+type ContainerEvents struct {
+	container  compcont.IEventContainer
+	listenerID int
+}
+
+// Close 停止监听容器事件
+func (e *ContainerEvents) Close() {
+	e.container.RemoveEventListener(e.listenerID)
+}
+
+func NewContainerEvents(cc compcont.IComponentContainer, cfg ContainerEventsConfig) (e *ContainerEvents, err error) {
+	container, ok := compcont.UnwrapContainer[compcont.IEventContainer](cc)
+	if !ok {
+		err = fmt.Errorf("%w, container %T does not support event listeners", compcont.ErrComponentTypeMismatch, cc)
+		return
+	}
+	logger, err := cfg.Logger.LoadComponent(cc)
+	if err != nil {
+		return
+	}
+	e = &ContainerEvents{
+		container:  container,
+		listenerID: container.AddEventListener(NewEventListener(logger.Instance)),
+	}
+	return
+}
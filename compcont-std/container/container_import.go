@@ -1,13 +1,12 @@
 package container
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
-	"os"
+	"slices"
 	"strings"
 
 	"github.com/go-compcont/compcont/compcont"
-	"gopkg.in/yaml.v3"
 )
 
 const ContainerImportType compcont.ComponentTypeID = "std.container-import"
@@ -15,42 +14,91 @@ const ContainerImportType compcont.ComponentTypeID = "std.container-import"
 type ImportFileConfig map[string]compcont.ComponentConfig
 
 type ContainerImportConfig struct {
-	FromFile string `ccf:"from_file"` // 从外部文件导入配置
+	FromFile string              `ccf:"from_file"` // 从外部文件导入配置
+	Overlays []string            `ccf:"overlays"`  // 按顺序叠加在from_file之上的配置文件
+	Profiles map[string][]string `ccf:"profiles"`  // 每个profile对应的叠加配置文件，在overlays之后叠加
+	Profile  string              `ccf:"profile"`   // 当前的profile，多个profile以逗号分隔并按顺序叠加，不填时使用容器的profile，可通过 compcont.ProfileFlag 或环境变量COMPCONT_PROFILE指定
+
+	Exports []compcont.ComponentName `ccf:"exports"` // 对父容器和兄弟容器可见的组件，不填时全部可见，使导入的配置文件只暴露稳定的组件
+
+	RegistryConfig `ccf:",squash"`
+}
+
+// 需要叠加的全部配置文件
+func (c ContainerImportConfig) overlayFiles(container compcont.IComponentContainer) (files []string, err error) {
+	files = slices.Clone(c.Overlays)
+	profile := c.Profile
+	if p, ok := container.(interface{ Profile() string }); ok && profile == "" {
+		profile = p.Profile()
+	}
+	if profile == "" {
+		return
+	}
+	for _, name := range strings.Split(profile, ",") {
+		name = strings.TrimSpace(name)
+		overlays, ok := c.Profiles[name]
+		if !ok {
+			// 容器的profile可能与该导入无关，只有显式指定的profile不存在时才报错
+			if c.Profile != "" {
+				err = fmt.Errorf("%w, profile %s is not defined", compcont.ErrComponentConfigInvalid, name)
+				return
+			}
+			continue
+		}
+		files = append(files, overlays...)
+	}
+	return
+}
+
+// 从配置文件导入组件的容器
+type importContainer struct {
+	wrappedContainer
+	config ContainerImportConfig
+	parent compcont.IComponentContainer
+}
+
+// 读取导入的配置文件及叠加的配置文件
+func (c *importContainer) loadComponents() (components []compcont.ComponentConfig, err error) {
+	overlays, err := c.config.overlayFiles(c.parent)
+	if err != nil {
+		return
+	}
+	return loadLayeredComponents(c.config.FromFile, overlays)
+}
+
+// ReloadSource implements compcont.ISourceContainer.
+func (c *importContainer) ReloadSource(ctx context.Context) (err error) {
+	components, err := c.loadComponents()
+	if err != nil {
+		return
+	}
+	return c.ReloadNamedComponents(components)
 }
 
 var importFactory compcont.IComponentFactory = &compcont.TypedSimpleComponentFactory[ContainerImportConfig, compcont.IComponentContainer]{
 	TypeID: ContainerImportType,
 	CreateInstanceFunc: func(ctx compcont.Context, config ContainerImportConfig) (instance compcont.IComponentContainer, err error) {
-		instance = compcont.NewComponentContainer(
-			compcont.WithFactoryRegistry(ctx.Container.FactoryRegistry()),
-			compcont.WithParentContainer(ctx.Container),
-			compcont.WithContext(ctx),
-		)
-		var bs []byte
-		bs, err = os.ReadFile(config.FromFile)
+		c := &importContainer{config: config, parent: ctx.Container}
+		components, err := c.loadComponents()
 		if err != nil {
 			return
 		}
-
-		components := []compcont.ComponentConfig{}
-		switch {
-		case strings.HasSuffix(config.FromFile, ".json"):
-			err = json.Unmarshal(bs, &components)
-			if err != nil {
-				return
-			}
-		case strings.HasSuffix(config.FromFile, ".yml") || strings.HasSuffix(config.FromFile, ".yaml"):
-			err = yaml.Unmarshal(bs, &components)
-			if err != nil {
-				return
-			}
-		default:
-			err = fmt.Errorf("unsupported config file format: %s", config.FromFile)
+		if err = compcont.CheckExports(config.Exports, components); err != nil {
 			return
 		}
-		err = instance.LoadNamedComponents(components)
+		c.wrappedContainer = wrappedContainer{compcont.NewComponentContainer(
+			compcont.WithFactoryRegistry(config.childRegistry(ctx.Container)),
+			compcont.WithParentContainer(ctx.Container),
+			compcont.WithContext(ctx),
+			compcont.WithExports(config.Exports),
+		)}
+		instance = c
+		err = loadChildComponents(ctx, instance, func() error {
+			return instance.LoadNamedComponents(components)
+		})
 		return
 	},
+	DestroyInstanceFunc: destroyContainer,
 }
 
 func MustRegisterContainerImport(r compcont.IFactoryRegistry) {
@@ -58,5 +106,5 @@ func MustRegisterContainerImport(r compcont.IFactoryRegistry) {
 }
 
 func init() {
-	MustRegisterContainerImport(compcont.DefaultFactoryRegistry)
+	compcont.AutoRegister(MustRegisterContainerImport)
 }
@@ -1,24 +1,38 @@
 package container
 
-import "github.com/go-compcont/compcont/compcont"
+import (
+	"errors"
+
+	"github.com/go-compcont/compcont/compcont"
+)
 
 const InlineContainerType compcont.ComponentTypeID = "std.container-inline"
 
 type ContainerInlineConfig struct {
 	Components []compcont.ComponentConfig `ccf:"components"`
+	Exports    []compcont.ComponentName   `ccf:"exports"` // 对父容器和兄弟容器可见的组件，不填时全部可见
+
+	RegistryConfig `ccf:",squash"`
 }
 
 var inlineFactory compcont.IComponentFactory = &compcont.TypedSimpleComponentFactory[ContainerInlineConfig, compcont.IComponentContainer]{
 	TypeID: InlineContainerType,
 	CreateInstanceFunc: func(ctx compcont.Context, config ContainerInlineConfig) (instance compcont.IComponentContainer, err error) {
+		if err = compcont.CheckExports(config.Exports, config.Components); err != nil {
+			return
+		}
 		instance = compcont.NewComponentContainer(
 			compcont.WithParentContainer(ctx.Container),
-			compcont.WithFactoryRegistry(ctx.Container.FactoryRegistry()),
+			compcont.WithFactoryRegistry(config.childRegistry(ctx.Container)),
 			compcont.WithContext(ctx),
+			compcont.WithExports(config.Exports),
 		)
-		err = instance.LoadNamedComponents(config.Components)
+		err = loadChildComponents(ctx, instance, func() error {
+			return instance.LoadNamedComponents(config.Components)
+		})
 		return
 	},
+	DestroyInstanceFunc: destroyContainer,
 }
 
 func MustRegisterContainerInline(r compcont.IFactoryRegistry) {
@@ -26,5 +40,48 @@ func MustRegisterContainerInline(r compcont.IFactoryRegistry) {
 }
 
 func init() {
-	MustRegisterContainerInline(compcont.DefaultFactoryRegistry)
+	compcont.AutoRegister(MustRegisterContainerInline)
+}
+
+// 销毁子容器时卸载其中的全部组件，依赖这些组件的其他容器中的组件也会被一并卸载
+func destroyContainer(ctx compcont.Context, instance compcont.IComponentContainer) (err error) {
+	return instance.UnloadNamedComponents(instance.LoadedComponentNames(), true)
+}
+
+// 加载子容器的组件，失败时卸载已经创建的组件，创建失败的组件实例不会被父容器销毁
+func loadChildComponents(ctx compcont.Context, instance compcont.IComponentContainer, load func() error) (err error) {
+	if err = load(); err != nil {
+		err = errors.Join(err, destroyContainer(ctx, instance))
+	}
+	return
+}
+
+// 包装了 compcont.NewComponentContainer 所创建容器的子容器实例，转发其实现的可选接口
+type wrappedContainer struct {
+	compcont.IComponentContainer
+}
+
+// Unwrap implements compcont.IContainerWrapper.
+func (c wrappedContainer) Unwrap() compcont.IComponentContainer {
+	return c.IComponentContainer
+}
+
+// Snapshot implements compcont.ISnapshotContainer.
+func (c wrappedContainer) Snapshot() compcont.ContainerSnapshot {
+	return c.IComponentContainer.(compcont.ISnapshotContainer).Snapshot()
+}
+
+// ReloadNamedComponents implements compcont.IReloadableContainer.
+func (c wrappedContainer) ReloadNamedComponents(configs []compcont.ComponentConfig) error {
+	return c.IComponentContainer.(compcont.IReloadableContainer).ReloadNamedComponents(configs)
+}
+
+// RebuildComponents implements compcont.IRebuildableContainer.
+func (c wrappedContainer) RebuildComponents(names []compcont.ComponentName) error {
+	return c.IComponentContainer.(compcont.IRebuildableContainer).RebuildComponents(names)
+}
+
+// Exported implements compcont.IExportingContainer.
+func (c wrappedContainer) Exported(name compcont.ComponentName) bool {
+	return c.IComponentContainer.(compcont.IExportingContainer).Exported(name)
 }
@@ -0,0 +1,101 @@
+package container
+
+import (
+	"context"
+	"errors"
+
+	"github.com/go-compcont/compcont/compcont"
+	"github.com/go-compcont/compcont/compcont-std/reloading"
+)
+
+const ContainerReloadingType compcont.ComponentTypeID = "std.container-reloading"
+
+// 从reloading源加载组件配置的容器，源数据变化时对容器进行热重载，只重建发生变化的组件及依赖它们的组件
+type ContainerReloadingConfig struct {
+	Exports []compcont.ComponentName `ccf:"exports"` // 对父容器和兄弟容器可见的组件，不填时全部可见
+
+	reloading.ReloadingConfigConfig[[]compcont.ComponentConfig] `ccf:",squash"`
+	RegistryConfig                                              `ccf:",squash"`
+}
+
+type reloadingContainer struct {
+	wrappedContainer
+	config     reloading.IReloadingConfig[[]compcont.ComponentConfig]
+	listenerID int
+	ownsSource bool // reloading源是否由该容器创建，通过refer引用的源由其所在容器负责关闭
+}
+
+// ReloadSource implements compcont.ISourceContainer.
+func (c *reloadingContainer) ReloadSource(ctx context.Context) (err error) {
+	components, err := c.config.LoadConfig(ctx)
+	if err != nil {
+		return
+	}
+	return c.ReloadNamedComponents(components)
+}
+
+var reloadingFactory compcont.IComponentFactory = &compcont.TypedSimpleComponentFactory[ContainerReloadingConfig, compcont.IComponentContainer]{
+	TypeID: ContainerReloadingType,
+	CreateInstanceFunc: func(ctx compcont.Context, config ContainerReloadingConfig) (instance compcont.IComponentContainer, err error) {
+		cc := compcont.NewComponentContainer(
+			compcont.WithParentContainer(ctx.Container),
+			compcont.WithFactoryRegistry(config.childRegistry(ctx.Container)),
+			compcont.WithContext(ctx),
+			compcont.WithExports(config.Exports),
+		)
+		rc, err := config.Build(ctx.Container)
+		if err != nil {
+			return
+		}
+		ownsSource := config.Reloading != nil && config.Reloading.Type != ""
+		defer func() {
+			if err != nil && ownsSource {
+				err = errors.Join(err, rc.Close())
+			}
+		}()
+		components, err := rc.LoadConfig(context.Background())
+		if err != nil {
+			return
+		}
+		if err = compcont.CheckExports(config.Exports, components); err != nil {
+			return
+		}
+		err = loadChildComponents(ctx, cc, func() error {
+			return cc.LoadNamedComponents(components)
+		})
+		if err != nil {
+			return
+		}
+		listenerID := rc.AddOnReloadingConfigListener(reloading.OnReloadingConfigListenerFunc[[]compcont.ComponentConfig](
+			func(_ context.Context, components []compcont.ComponentConfig) error {
+				return cc.(compcont.IReloadableContainer).ReloadNamedComponents(components)
+			},
+		))
+		instance = &reloadingContainer{
+			wrappedContainer: wrappedContainer{cc},
+			config:           rc,
+			listenerID:       listenerID,
+			ownsSource:       ownsSource,
+		}
+		return
+	},
+	DestroyInstanceFunc: func(ctx compcont.Context, instance compcont.IComponentContainer) (err error) {
+		if c, ok := instance.(*reloadingContainer); ok {
+			c.config.RemoveOnReloadingConfigListener(c.listenerID)
+			if c.ownsSource {
+				if err = c.config.Close(); err != nil {
+					return
+				}
+			}
+		}
+		return destroyContainer(ctx, instance)
+	},
+}
+
+func MustRegisterContainerReloading(r compcont.IFactoryRegistry) {
+	compcont.MustRegister(r, reloadingFactory)
+}
+
+func init() {
+	compcont.AutoRegister(MustRegisterContainerReloading)
+}
@@ -1,12 +1,15 @@
 package container
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"reflect"
 	"testing"
 
 	"github.com/go-compcont/compcont/compcont"
+	"github.com/go-compcont/compcont/compcont-std/reloading"
 	"github.com/stretchr/testify/assert"
 	"gopkg.in/yaml.v3"
 )
@@ -86,6 +89,11 @@ const cfgYaml = `
         type: "output"
         config: { refer: "../output_test4" }
 
+      - name: cross
+        type: "echo"
+        deps: ["../test3"]
+        config: "Container cross"
+
 - name: c2
   type: "std.container-import"
   deps: [c1]
@@ -94,13 +102,233 @@ const cfgYaml = `
 `
 
 func TestFinder(t *testing.T) {
-	cc := compcont.NewComponentContainer()
-
-	compcont.DefaultFactoryRegistry.Register(testComp)
-	compcont.DefaultFactoryRegistry.Register(outputIns)
+	registry := compcont.NewFactoryRegistry()
+	MustRegister(registry)
+	compcont.MustRegister(registry, testComp, outputIns)
+	cc := compcont.NewComponentContainer(compcont.WithFactoryRegistry(registry))
 	cfg := []compcont.ComponentConfig{}
 	err := yaml.Unmarshal([]byte(cfgYaml), &cfg)
 	assert.NoError(t, err)
 	err = cc.LoadNamedComponents(cfg)
 	assert.NoError(t, err)
+
+	c1, err := compcont.GetComponent[compcont.IComponentContainer](cc, "c1")
+	assert.NoError(t, err)
+	_, err = c1.Instance.GetComponent("cross")
+	assert.NoError(t, err)
+
+	// test1仍被其他组件依赖，不能直接卸载
+	err = cc.UnloadNamedComponents([]compcont.ComponentName{"test1"}, false)
+	assert.ErrorIs(t, err, compcont.ErrComponentHasDependents)
+
+	// 递归卸载时会跨容器卸载所有直接或间接依赖test1的组件
+	err = cc.UnloadNamedComponents([]compcont.ComponentName{"test1"}, true)
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []compcont.ComponentName{"test2"}, cc.LoadedComponentNames())
+	assert.Empty(t, c1.Instance.LoadedComponentNames())
+}
+
+func TestModuleContainer(t *testing.T) {
+	base := &compcont.Module{Name: "test.base", Factories: []compcont.IComponentFactory{testComp}}
+	compcont.MustRegisterModule(&compcont.Module{
+		Name:      "test.greetings",
+		Imports:   []*compcont.Module{base},
+		Factories: []compcont.IComponentFactory{testComp},
+		Components: []compcont.ComponentConfig{
+			{Name: "hello", Type: "echo", Config: "hello"},
+			{Name: "bye", Type: "echo", Config: "bye"},
+		},
+	})
+	registry := compcont.NewFactoryRegistry()
+	MustRegisterModuleContainer(registry)
+	cc := compcont.NewComponentContainer(compcont.WithFactoryRegistry(registry))
+	cfg := []compcont.ComponentConfig{}
+	err := yaml.Unmarshal([]byte(`
+- name: greetings
+  type: std.module
+  config:
+    module: test.greetings
+    components:
+      - { name: bye, config: "see you" }
+- { name: greeting, refer: greetings/bye }
+`), &cfg)
+	assert.NoError(t, err)
+	assert.NoError(t, cc.LoadNamedComponents(cfg))
+
+	greeting, err := compcont.GetComponent[any](cc, "greeting")
+	assert.NoError(t, err)
+	assert.Equal(t, "see you", greeting.Instance)
+
+	// 模块名称和导入的模块记录在子容器的快照和依赖图中
+	modules := []compcont.ModuleSnapshot{{Name: "test.greetings", Imports: []string{"test.base"}}}
+	snapshot := cc.(compcont.ISnapshotContainer).Snapshot()
+	assert.Equal(t, modules, snapshot.Components[1].Container.Modules)
+	graph := snapshot.Graph()
+	assert.Equal(t, modules, graph.Nodes[1].Modules)
+	assert.Contains(t, graph.DOT(), `label="/greetings\nstd.module\nmodule test.greetings (imports test.base)"`)
+}
+
+func TestChildContainerRegistry(t *testing.T) {
+	registry := compcont.NewFactoryRegistry()
+	MustRegisterContainerInline(registry)
+	compcont.MustRegister(registry, testComp)
+	cc := compcont.NewComponentContainer(compcont.WithFactoryRegistry(registry))
+	cfg := []compcont.ComponentConfig{}
+	err := yaml.Unmarshal([]byte(`
+- name: sandbox
+  type: std.container-inline
+  config:
+    allow_types: ["std.*"]
+    components:
+      - { name: inner, type: std.container-inline, config: { components: [] } }
+`), &cfg)
+	assert.NoError(t, err)
+	assert.NoError(t, cc.LoadNamedComponents(cfg))
+
+	sandbox, err := compcont.GetComponent[compcont.IComponentContainer](cc, "sandbox")
+	assert.NoError(t, err)
+	inner, err := compcont.GetComponent[compcont.IComponentContainer](sandbox.Instance, "inner")
+	assert.NoError(t, err)
+	// 孙容器同样受到限制
+	err = inner.Instance.LoadNamedComponents([]compcont.ComponentConfig{{Name: "e", Type: "echo", Config: "e"}})
+	assert.ErrorIs(t, err, compcont.ErrComponentTypeNotAllowed)
+
+	// 在子容器中注册的工厂对父容器不可见
+	compcont.MustRegister(sandbox.Instance.FactoryRegistry(), &compcont.TypedSimpleComponentFactory[string, any]{
+		TypeID: "std.local",
+		CreateInstanceFunc: func(ctx compcont.Context, config string) (instance any, err error) {
+			return config, nil
+		},
+	})
+	assert.NoError(t, sandbox.Instance.LoadNamedComponents([]compcont.ComponentConfig{{Name: "l", Type: "std.local", Config: "l"}}))
+	_, err = registry.GetFactory("std.local")
+	assert.ErrorIs(t, err, compcont.ErrComponentTypeNotRegistered)
+}
+
+func TestChildContainerExports(t *testing.T) {
+	registry := compcont.NewFactoryRegistry()
+	MustRegisterContainerInline(registry)
+	compcont.MustRegister(registry, testComp)
+	cc := compcont.NewComponentContainer(compcont.WithFactoryRegistry(registry))
+	cfg := []compcont.ComponentConfig{}
+	err := yaml.Unmarshal([]byte(`
+- name: db
+  type: std.container-inline
+  config:
+    exports: [client]
+    components:
+      - { name: pool, type: echo, config: pool }
+      - { name: client, refer: pool }
+      - { name: inner, type: std.container-inline, config: { components: [{ name: x, refer: ../pool }] } }
+- { name: client, refer: db/client }
+`), &cfg)
+	assert.NoError(t, err)
+	assert.NoError(t, cc.LoadNamedComponents(cfg))
+
+	client, err := compcont.GetComponent[string](cc, "client")
+	assert.NoError(t, err)
+	assert.Equal(t, "pool", client.Instance)
+
+	// 未导出的组件对父容器和兄弟容器不可见，子容器内部仍可以访问
+	err = cc.LoadNamedComponents([]compcont.ComponentConfig{{Name: "pool", Refer: "db/pool"}})
+	assert.ErrorIs(t, err, compcont.ErrComponentNotExported)
+	err = cc.LoadNamedComponents([]compcont.ComponentConfig{{Name: "echo", Type: "echo", Config: "e", Deps: []compcont.ComponentName{"/db/pool"}}})
+	assert.ErrorIs(t, err, compcont.ErrComponentNotExported)
+
+	found, err := compcont.FindComponentsByType[string](cc, compcont.FindInDescendants())
+	assert.NoError(t, err)
+	assert.Len(t, found, 1)
+
+	// exports中的组件不存在时在创建任何组件之前加载失败
+	var created, destroyed []compcont.ComponentName
+	compcont.MustRegister(registry, &compcont.TypedSimpleComponentFactory[any, any]{
+		TypeID: "tracked",
+		CreateInstanceFunc: func(ctx compcont.Context, config any) (instance any, err error) {
+			if config == "fail" {
+				err = errors.New("create failed")
+				return
+			}
+			created = append(created, ctx.Config.Name)
+			instance = ctx.Config.Name
+			return
+		},
+		DestroyInstanceFunc: func(ctx compcont.Context, instance any) (err error) {
+			destroyed = append(destroyed, ctx.Config.Name)
+			return
+		},
+	})
+	err = cc.LoadNamedComponents([]compcont.ComponentConfig{{
+		Name:   "typo",
+		Type:   InlineContainerType,
+		Config: map[string]any{"exports": []any{"clinet"}, "components": []any{map[string]any{"name": "client", "type": "tracked"}}},
+	}})
+	assert.ErrorIs(t, err, compcont.ErrComponentConfigInvalid)
+	assert.ErrorContains(t, err, "clinet")
+	assert.Empty(t, created)
+
+	// 子容器加载失败时销毁已经创建的组件
+	err = cc.LoadNamedComponents([]compcont.ComponentConfig{{
+		Name: "partial",
+		Type: InlineContainerType,
+		Config: map[string]any{"components": []any{
+			map[string]any{"name": "server", "type": "tracked"},
+			map[string]any{"name": "broken", "type": "tracked", "config": "fail", "deps": []any{"server"}},
+		}},
+	}})
+	assert.Error(t, err)
+	assert.Equal(t, []compcont.ComponentName{"server"}, created)
+	assert.Equal(t, []compcont.ComponentName{"server"}, destroyed)
+}
+
+type fakeReloading struct {
+	data   []byte
+	closed bool
+}
+
+func (r *fakeReloading) Load(ctx context.Context) []byte { return r.data }
+
+func (r *fakeReloading) AddOnReloadingListener(listener reloading.OnReloadingListener) int {
+	return 0
+}
+
+func (r *fakeReloading) RemoveOnReloadingListener(id int) {}
+
+func (r *fakeReloading) Close() error {
+	r.closed = true
+	return nil
+}
+
+func TestContainerReloadingSource(t *testing.T) {
+	sources := make(map[string]*fakeReloading)
+	registry := compcont.NewFactoryRegistry()
+	MustRegisterContainerReloading(registry)
+	compcont.MustRegister(registry, testComp, &compcont.TypedSimpleComponentFactory[string, reloading.IReloading]{
+		TypeID: "fake-reloading",
+		CreateInstanceFunc: func(ctx compcont.Context, config string) (instance reloading.IReloading, err error) {
+			sources[config] = &fakeReloading{data: []byte(config)}
+			return sources[config], nil
+		},
+	})
+	cc := compcont.NewComponentContainer(compcont.WithFactoryRegistry(registry))
+	cfg := []compcont.ComponentConfig{}
+	err := yaml.Unmarshal([]byte(`
+- { name: source, type: fake-reloading, config: '[{ name: e, type: echo, config: e }]' }
+- { name: shared, type: std.container-reloading, config: { reloading: { refer: source } } }
+- { name: owned, type: std.container-reloading, config: { reloading: { type: fake-reloading, config: '[]' } } }
+`), &cfg)
+	assert.NoError(t, err)
+	assert.NoError(t, cc.LoadNamedComponents(cfg))
+	assert.Len(t, sources, 2)
+
+	// 可以从reloading容器中获取组件句柄
+	shared, err := compcont.GetComponent[compcont.IComponentContainer](cc, "shared")
+	assert.NoError(t, err)
+	h, err := compcont.GetHandle[any](shared.Instance, "e")
+	assert.NoError(t, err)
+	assert.Equal(t, "e", h.Get())
+
+	// 通过refer引用的源仍被其他组件使用，不随容器关闭，以type声明的源由容器自己关闭
+	assert.NoError(t, cc.UnloadNamedComponents([]compcont.ComponentName{"shared", "owned"}, false))
+	assert.False(t, sources["[{ name: e, type: echo, config: e }]"].closed)
+	assert.True(t, sources["[]"].closed)
 }
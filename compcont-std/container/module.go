@@ -0,0 +1,50 @@
+package container
+
+import "github.com/go-compcont/compcont/compcont"
+
+const ModuleContainerType compcont.ComponentTypeID = "std.module"
+
+type ModuleConfig struct {
+	Module     string                     `ccf:"module"`     // 通过 compcont.RegisterModule 注册的模块名称
+	Components []compcont.ComponentConfig `ccf:"components"` // 按名称覆盖模块的默认组件，或追加新的组件
+	Exports    []compcont.ComponentName   `ccf:"exports"`    // 对父容器和兄弟容器可见的组件，不填时使用模块声明的exports
+
+	RegistryConfig `ccf:",squash"`
+}
+
+// 将模块加载到一个子容器中，模块的组件可以通过 模块组件名/组件名 引用
+var moduleFactory compcont.IComponentFactory = &compcont.TypedSimpleComponentFactory[ModuleConfig, compcont.IComponentContainer]{
+	TypeID: ModuleContainerType,
+	CreateInstanceFunc: func(ctx compcont.Context, config ModuleConfig) (instance compcont.IComponentContainer, err error) {
+		module, err := compcont.GetModule(config.Module)
+		if err != nil {
+			return
+		}
+		exports := config.Exports
+		if exports == nil {
+			exports = module.Exports
+		}
+		if err = compcont.CheckExports(exports, module.ComponentConfigs(config.Components)); err != nil {
+			return
+		}
+		instance = compcont.NewComponentContainer(
+			compcont.WithParentContainer(ctx.Container),
+			compcont.WithFactoryRegistry(config.childRegistry(ctx.Container)),
+			compcont.WithContext(ctx),
+			compcont.WithExports(exports),
+		)
+		err = loadChildComponents(ctx, instance, func() error {
+			return compcont.LoadModule(instance, module, config.Components)
+		})
+		return
+	},
+	DestroyInstanceFunc: destroyContainer,
+}
+
+func MustRegisterModuleContainer(r compcont.IFactoryRegistry) {
+	compcont.MustRegister(r, moduleFactory)
+}
+
+func init() {
+	compcont.AutoRegister(MustRegisterModuleContainer)
+}
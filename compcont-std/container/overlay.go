@@ -0,0 +1,158 @@
+package container
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/go-compcont/compcont/compcont"
+	"gopkg.in/yaml.v3"
+)
+
+// 叠加配置中的特殊字段和列表修补后缀
+const (
+	overlayDeleteKey    = "$delete" // 组件上设置为true时从基础配置中删除该组件
+	overlayAppendSuffix = "+"       // 如 middlewares+，将列表元素追加到基础配置的列表之后
+	overlayRemoveSuffix = "-"       // 如 middlewares-，从基础配置的列表中删除相等的元素
+)
+
+// 读取组件配置文件，根据扩展名选择json或yaml格式
+func readComponentsFile(path string) (components []any, err error) {
+	bs, err := os.ReadFile(path)
+	if err != nil {
+		return
+	}
+	switch {
+	case strings.HasSuffix(path, ".json"):
+		err = json.Unmarshal(bs, &components)
+	case strings.HasSuffix(path, ".yml") || strings.HasSuffix(path, ".yaml"):
+		err = yaml.Unmarshal(bs, &components)
+	default:
+		err = fmt.Errorf("unsupported config file format: %s", path)
+	}
+	if err != nil {
+		err = fmt.Errorf("read components file %s failed, %w", path, err)
+	}
+	return
+}
+
+// 依次读取基础配置和叠加配置并合并为最终的组件配置
+func loadLayeredComponents(base string, overlays []string) (components []compcont.ComponentConfig, err error) {
+	merged, err := readComponentsFile(base)
+	if err != nil {
+		return
+	}
+	for _, overlay := range overlays {
+		var layer []any
+		layer, err = readComponentsFile(overlay)
+		if err != nil {
+			return
+		}
+		merged, err = mergeNamedList(merged, layer)
+		if err != nil {
+			err = fmt.Errorf("merge overlay %s failed, %w", overlay, err)
+			return
+		}
+	}
+
+	// 合并后的通用结构重新编码再解码为组件配置
+	bs, err := yaml.Marshal(merged)
+	if err != nil {
+		return
+	}
+	err = yaml.Unmarshal(bs, &components)
+	return
+}
+
+func itemName(item any) (name string, ok bool) {
+	m, ok := item.(map[string]any)
+	if !ok {
+		return
+	}
+	name, ok = m["name"].(string)
+	return
+}
+
+// 判断列表是否为具名元素的列表，此类列表按名称合并
+func isNamedList(list []any) bool {
+	for _, item := range list {
+		if _, ok := itemName(item); !ok {
+			return false
+		}
+	}
+	return len(list) > 0
+}
+
+// 按名称合并具名元素的列表：同名元素深度合并，标记了$delete的元素被删除，新的元素追加在末尾
+func mergeNamedList(base, overlay []any) (merged []any, err error) {
+	merged = slices.Clone(base)
+	for _, item := range overlay {
+		name, ok := itemName(item)
+		if !ok {
+			err = fmt.Errorf("overlay item %v has no name", item)
+			return
+		}
+		overlayItem := item.(map[string]any)
+		index := slices.IndexFunc(merged, func(baseItem any) bool {
+			baseName, _ := itemName(baseItem)
+			return baseName == name
+		})
+		if deleted, _ := overlayItem[overlayDeleteKey].(bool); deleted {
+			if index < 0 {
+				err = fmt.Errorf("component %s to delete does not exist", name)
+				return
+			}
+			merged = slices.Delete(merged, index, index+1)
+			continue
+		}
+		if index < 0 {
+			merged = append(merged, mergeValue(nil, overlayItem))
+			continue
+		}
+		merged[index] = mergeValue(merged[index], overlayItem)
+	}
+	return
+}
+
+// 深度合并：map逐个字段合并，具名列表按名称合并，其余值直接被叠加配置替换
+func mergeValue(base, overlay any) any {
+	overlayMap, ok := overlay.(map[string]any)
+	if !ok {
+		baseList, baseOk := base.([]any)
+		overlayList, overlayOk := overlay.([]any)
+		if baseOk && overlayOk && isNamedList(baseList) && isNamedList(overlayList) {
+			if merged, err := mergeNamedList(baseList, overlayList); err == nil {
+				return merged
+			}
+		}
+		return overlay
+	}
+	baseMap, _ := base.(map[string]any)
+	merged := make(map[string]any, len(baseMap)+len(overlayMap))
+	for k, v := range baseMap {
+		merged[k] = v
+	}
+	for k, v := range overlayMap {
+		switch {
+		case k == overlayDeleteKey:
+		case strings.HasSuffix(k, overlayAppendSuffix):
+			key := strings.TrimSuffix(k, overlayAppendSuffix)
+			list, _ := merged[key].([]any)
+			items, _ := v.([]any)
+			merged[key] = append(slices.Clone(list), items...)
+		case strings.HasSuffix(k, overlayRemoveSuffix):
+			key := strings.TrimSuffix(k, overlayRemoveSuffix)
+			list, _ := merged[key].([]any)
+			items, _ := v.([]any)
+			merged[key] = slices.DeleteFunc(slices.Clone(list), func(item any) bool {
+				return slices.ContainsFunc(items, func(removed any) bool { return reflect.DeepEqual(item, removed) })
+			})
+		default:
+			merged[k] = mergeValue(merged[k], v)
+		}
+	}
+	return merged
+}
@@ -0,0 +1,108 @@
+package container
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-compcont/compcont/compcont"
+	"github.com/stretchr/testify/assert"
+)
+
+type listConfig struct {
+	Items []string `ccf:"items"`
+}
+
+var listComp compcont.IComponentFactory = &compcont.TypedSimpleComponentFactory[listConfig, []string]{
+	TypeID: "list",
+	CreateInstanceFunc: func(ctx compcont.Context, config listConfig) (instance []string, err error) {
+		return config.Items, nil
+	},
+}
+
+func TestContainerImportOverlays(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		assert.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+		return path
+	}
+	base := write("base.yaml", `
+- { name: greeting, type: echo, config: "hello base" }
+- { name: debug, type: echo, config: "debug" }
+- { name: middlewares, type: list, config: { items: [recovery, zap] } }
+- name: inner
+  type: std.container-inline
+  config:
+    components:
+      - { name: a, type: echo, config: "inner a" }
+      - { name: b, type: echo, config: "inner b" }
+`)
+	prod := write("prod.yaml", `
+- { name: greeting, config: "hello prod" }
+- { name: debug, $delete: true }
+- { name: middlewares, config: { items+: [prometheus], items-: [zap] } }
+- name: inner
+  config:
+    components:
+      - { name: a, config: "prod a" }
+      - { name: b, $delete: true }
+`)
+	local := write("local.yaml", `
+- { name: extra, type: echo, config: "local" }
+`)
+
+	r := compcont.NewFactoryRegistry()
+	compcont.MustRegister(r, testComp)
+	compcont.MustRegister(r, listComp)
+	MustRegisterContainerImport(r)
+	MustRegisterContainerInline(r)
+	cc := compcont.NewComponentContainer(compcont.WithFactoryRegistry(r), compcont.WithProfile("prod,local"))
+	err := cc.LoadNamedComponents([]compcont.ComponentConfig{{
+		Name: "imported",
+		Type: ContainerImportType,
+		Config: map[string]any{
+			"from_file": base,
+			"profiles":  map[string]any{"prod": []any{prod}, "local": []any{local}},
+		},
+	}})
+	assert.NoError(t, err)
+
+	imported, err := compcont.GetComponent[compcont.IComponentContainer](cc, "imported")
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []compcont.ComponentName{"greeting", "middlewares", "inner", "extra"}, imported.Instance.LoadedComponentNames())
+	greeting, err := compcont.GetComponent[any](imported.Instance, "greeting")
+	assert.NoError(t, err)
+	assert.Equal(t, "hello prod", greeting.Instance)
+	middlewares, err := compcont.GetComponent[[]string](imported.Instance, "middlewares")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"recovery", "prometheus"}, middlewares.Instance)
+
+	inner, err := compcont.GetComponent[compcont.IComponentContainer](imported.Instance, "inner")
+	assert.NoError(t, err)
+	assert.Equal(t, []compcont.ComponentName{"a"}, inner.Instance.LoadedComponentNames())
+	a, err := compcont.GetComponent[any](inner.Instance, "a")
+	assert.NoError(t, err)
+	assert.Equal(t, "prod a", a.Instance)
+
+	// 修改配置文件后重新读取来源，只重建发生变化的组件
+	write("local.yaml", `
+- { name: extra, type: echo, config: "local changed" }
+`)
+	assert.NoError(t, imported.Instance.(compcont.ISourceContainer).ReloadSource(context.Background()))
+	extra, err := compcont.GetComponent[any](imported.Instance, "extra")
+	assert.NoError(t, err)
+	assert.Equal(t, "local changed", extra.Instance)
+	reloadedInner, err := compcont.GetComponent[compcont.IComponentContainer](imported.Instance, "inner")
+	assert.NoError(t, err)
+	assert.Same(t, inner.Instance, reloadedInner.Instance)
+
+	// 显式指定的profile不存在时报错
+	err = cc.LoadNamedComponents([]compcont.ComponentConfig{{
+		Name:   "missing",
+		Type:   ContainerImportType,
+		Config: map[string]any{"from_file": base, "profile": "staging", "profiles": map[string]any{"prod": []any{prod}}},
+	}})
+	assert.ErrorIs(t, err, compcont.ErrComponentConfigInvalid)
+}
@@ -0,0 +1,27 @@
+package container
+
+import "github.com/go-compcont/compcont/compcont"
+
+// 子容器的组件工厂配置，子容器使用叠加在父容器之上的注册器，在子容器中注册的工厂不影响父容器
+type RegistryConfig struct {
+	AllowTypes []compcont.ComponentTypeID `ccf:"allow_types"` // 允许在子容器中实例化的组件类型，支持通配符如 contrib.*，为空时不限制
+	DenyTypes  []compcont.ComponentTypeID `ccf:"deny_types"`  // 禁止在子容器中实例化的组件类型，优先于allow_types
+}
+
+// 为子容器创建注册器，父容器的限制对子容器同样生效
+func (c RegistryConfig) childRegistry(parent compcont.IComponentContainer) (registry compcont.IFactoryRegistry) {
+	registry = compcont.NewChildFactoryRegistry(parent.FactoryRegistry())
+	if len(c.AllowTypes) > 0 || len(c.DenyTypes) > 0 {
+		registry = compcont.NewFilteredFactoryRegistry(registry, compcont.FactoryFilter{Allow: c.AllowTypes, Deny: c.DenyTypes})
+	}
+	return
+}
+
+// Register 注册包中全部的容器类型
+func Register(registry compcont.IFactoryRegistry) error {
+	return compcont.Register(registry, inlineFactory, importFactory, reloadingFactory, moduleFactory)
+}
+
+func MustRegister(registry compcont.IFactoryRegistry) {
+	compcont.MustRegister(registry, inlineFactory, importFactory, reloadingFactory, moduleFactory)
+}
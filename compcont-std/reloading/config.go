@@ -52,10 +52,12 @@ func NewReloadingConfig[T any](opt ReloadingConfigOption[T]) IReloadingConfig[T]
 		configType:   opt.ConfigType,
 		structMode:   opt.StructMode,
 	}
-	opt.Reloading.AddOnReloadingListener(OnReloadingListenerFunc(func(ctx context.Context, data []byte) error {
-		ret.currentConfig = nil
-		return nil
-	}))
+	if opt.Reloading != nil {
+		opt.Reloading.AddOnReloadingListener(OnReloadingListenerFunc(func(ctx context.Context, data []byte) error {
+			ret.currentConfig = nil
+			return nil
+		}))
+	}
 	return ret
 }
 
@@ -140,6 +142,9 @@ func (r *ReloadingConfig[T]) RemoveOnReloadingConfigListener(id int) {
 }
 
 func (r *ReloadingConfig[T]) Close() error {
+	if r.innerRaw == nil {
+		return nil
+	}
 	return r.innerRaw.Close()
 }
 
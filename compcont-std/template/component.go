@@ -0,0 +1,26 @@
+package template
+
+import (
+	"github.com/go-compcont/compcont/compcont"
+)
+
+const TypeID compcont.ComponentTypeID = "std.template"
+
+var factory compcont.IComponentFactory = &compcont.TypedSimpleComponentFactory[Config, *Template]{
+	TypeID: TypeID,
+	CreateInstanceFunc: func(ctx compcont.Context, cfg Config) (instance *Template, err error) {
+		return New(cfg)
+	},
+}
+
+func Register(registry compcont.IFactoryRegistry) error {
+	return compcont.Register(registry, factory)
+}
+
+func MustRegister(registry compcont.IFactoryRegistry) {
+	compcont.MustRegister(registry, factory)
+}
+
+func init() {
+	compcont.AutoRegister(MustRegister)
+}
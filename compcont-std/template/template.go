@@ -0,0 +1,278 @@
+package template
+
+import (
+	"fmt"
+	"maps"
+	"math"
+	"regexp"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/go-compcont/compcont/compcont"
+)
+
+type ParamType string
+
+const (
+	ParamTypeAny      ParamType = "any" // 不做类型检查，默认值
+	ParamTypeString   ParamType = "string"
+	ParamTypeInt      ParamType = "int"
+	ParamTypeFloat    ParamType = "float"
+	ParamTypeBool     ParamType = "bool"
+	ParamTypeDuration ParamType = "duration" // 如 5s，实例化为 time.Duration
+	ParamTypeList     ParamType = "list"
+	ParamTypeMap      ParamType = "map"
+)
+
+type ParamSpec struct {
+	Type        ParamType `ccf:"type"`        // 参数类型，字符串形式的值会被转换为对应类型，如通过环境变量覆盖的参数
+	Default     any       `ccf:"default"`     // 未传入时的默认值
+	Required    bool      `ccf:"required"`    // 为true时必须传入
+	Description string    `ccf:"description"` // 参数说明
+}
+
+type Config struct {
+	Params    map[string]ParamSpec `ccf:"params"`    // 模板声明的参数，未声明的参数不能被引用或传入
+	Component map[string]any       `ccf:"component"` // 组件配置模板，支持type、refer、deps、config、lazy、scope，字符串中的 ${param} 被替换为参数值，$$ 表示 $
+}
+
+// 字符串整体为 ${param} 时替换为参数的原始类型的值，否则以字符串形式拼接
+var placeholderPattern = regexp.MustCompile(`\$\$|\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// Template std.template 组件的实例，实现了 compcont.IComponentTemplate
+type Template struct {
+	config Config
+}
+
+func New(cfg Config) (t *Template, err error) {
+	for name, spec := range cfg.Params {
+		if spec.Type == "" {
+			spec.Type = ParamTypeAny
+		}
+		if !slices.Contains([]ParamType{ParamTypeAny, ParamTypeString, ParamTypeInt, ParamTypeFloat, ParamTypeBool, ParamTypeDuration, ParamTypeList, ParamTypeMap}, spec.Type) {
+			err = fmt.Errorf("param %s has unknown type %s", name, spec.Type)
+			return
+		}
+		if spec.Default != nil {
+			if spec.Default, err = coerceParam(spec.Type, spec.Default); err != nil {
+				err = fmt.Errorf("default value of param %s is invalid, %w", name, err)
+				return
+			}
+		}
+		cfg.Params[name] = spec
+	}
+	if len(cfg.Component) == 0 {
+		err = fmt.Errorf("component of template is required")
+		return
+	}
+	// 加载模板时即检查引用了未声明参数的位置，而不是等到实例化时
+	if _, err = substitute(cfg.Component, "component", func(name string) (any, bool) {
+		_, ok := cfg.Params[name]
+		return nil, ok
+	}); err != nil {
+		return
+	}
+	t = &Template{config: cfg}
+	return
+}
+
+func (t *Template) Instantiate(params map[string]any) (config compcont.ComponentConfig, err error) {
+	for name := range params {
+		if _, ok := t.config.Params[name]; !ok {
+			err = fmt.Errorf("unknown param %s", name)
+			return
+		}
+	}
+	values := make(map[string]any, len(t.config.Params))
+	for name, spec := range t.config.Params {
+		value, ok := params[name]
+		switch {
+		case ok:
+			if value, err = coerceParam(spec.Type, value); err != nil {
+				err = fmt.Errorf("param %s is invalid, %w", name, err)
+				return
+			}
+		case spec.Required:
+			err = fmt.Errorf("param %s is required", name)
+			return
+		default:
+			value = spec.Default
+		}
+		values[name] = value
+	}
+	component, err := substitute(t.config.Component, "component", func(name string) (value any, ok bool) {
+		value, ok = values[name]
+		return
+	})
+	if err != nil {
+		return
+	}
+	return toComponentConfig(component.(map[string]any))
+}
+
+// 深度复制value并替换其中字符串的参数引用，path用于在错误中指出模板中的位置
+func substitute(value any, path string, lookup func(name string) (any, bool)) (ret any, err error) {
+	switch v := value.(type) {
+	case string:
+		return substituteString(v, path, lookup)
+	case map[string]any:
+		m := make(map[string]any, len(v))
+		for _, k := range slices.Sorted(maps.Keys(v)) {
+			if m[k], err = substitute(v[k], path+"."+k, lookup); err != nil {
+				return
+			}
+		}
+		return m, nil
+	case []any:
+		list := make([]any, len(v))
+		for i, item := range v {
+			if list[i], err = substitute(item, path+"["+strconv.Itoa(i)+"]", lookup); err != nil {
+				return
+			}
+		}
+		return list, nil
+	default:
+		return value, nil
+	}
+}
+
+func substituteString(s string, path string, lookup func(name string) (any, bool)) (ret any, err error) {
+	if m := placeholderPattern.FindStringSubmatch(s); m != nil && m[0] == s && m[1] != "" {
+		value, ok := lookup(m[1])
+		if !ok {
+			err = fmt.Errorf("%s refers to undeclared param %s", path, m[1])
+			return
+		}
+		return value, nil
+	}
+	ret = placeholderPattern.ReplaceAllStringFunc(s, func(match string) string {
+		if match == "$$" {
+			return "$"
+		}
+		name := match[2 : len(match)-1]
+		value, ok := lookup(name)
+		if !ok {
+			if err == nil {
+				err = fmt.Errorf("%s refers to undeclared param %s", path, name)
+			}
+			return match
+		}
+		if value == nil {
+			return ""
+		}
+		return fmt.Sprint(value)
+	})
+	return
+}
+
+// 将参数值转换为声明的类型
+func coerceParam(typ ParamType, value any) (ret any, err error) {
+	if value == nil || typ == ParamTypeAny || typ == "" {
+		return value, nil
+	}
+	s, isString := value.(string)
+	switch typ {
+	case ParamTypeString:
+		if isString {
+			return s, nil
+		}
+	case ParamTypeInt:
+		switch v := value.(type) {
+		case int:
+			return v, nil
+		case int64:
+			return int(v), nil
+		case uint64:
+			if v > math.MaxInt {
+				err = fmt.Errorf("%d overflows int", v)
+				return
+			}
+			return int(v), nil
+		case float64:
+			if v == float64(int(v)) {
+				return int(v), nil
+			}
+		case string:
+			return strconv.Atoi(s)
+		}
+	case ParamTypeFloat:
+		switch v := value.(type) {
+		case float64:
+			return v, nil
+		case int:
+			return float64(v), nil
+		case int64:
+			return float64(v), nil
+		case string:
+			return strconv.ParseFloat(s, 64)
+		}
+	case ParamTypeBool:
+		switch v := value.(type) {
+		case bool:
+			return v, nil
+		case string:
+			return strconv.ParseBool(s)
+		}
+	case ParamTypeDuration:
+		switch v := value.(type) {
+		case time.Duration:
+			return v, nil
+		case string:
+			return time.ParseDuration(s)
+		}
+	case ParamTypeList:
+		if v, ok := value.([]any); ok {
+			return v, nil
+		}
+	case ParamTypeMap:
+		if v, ok := value.(map[string]any); ok {
+			return v, nil
+		}
+	}
+	err = fmt.Errorf("expected %s, but got %T", typ, value)
+	return
+}
+
+// 将替换后的组件配置模板转换为组件配置
+func toComponentConfig(m map[string]any) (config compcont.ComponentConfig, err error) {
+	for _, k := range slices.Sorted(maps.Keys(m)) {
+		v := m[k]
+		ok := true
+		switch k {
+		case "type":
+			var s string
+			s, ok = v.(string)
+			config.Type = compcont.ComponentTypeID(s)
+		case "refer":
+			config.Refer, ok = v.(string)
+		case "scope":
+			var s string
+			s, ok = v.(string)
+			config.Scope = compcont.ComponentScope(s)
+		case "lazy":
+			config.Lazy, ok = v.(bool)
+		case "config":
+			config.Config = v
+		case "deps":
+			var deps []any
+			deps, ok = v.([]any)
+			for _, dep := range deps {
+				name, isString := dep.(string)
+				if !isString {
+					ok = false
+					break
+				}
+				config.Deps = append(config.Deps, compcont.ComponentName(name))
+			}
+		default:
+			err = fmt.Errorf("component.%s is not supported in template", k)
+			return
+		}
+		if !ok {
+			err = fmt.Errorf("component.%s has invalid value %v", k, v)
+			return
+		}
+	}
+	return
+}
@@ -0,0 +1,136 @@
+package template
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-compcont/compcont/compcont"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+)
+
+type clientConfig struct {
+	URL     string        `ccf:"url"`
+	DB      int           `ccf:"db"`
+	Timeout time.Duration `ccf:"timeout"`
+	Debug   bool          `ccf:"debug"`
+}
+
+var clientComp compcont.IComponentFactory = &compcont.TypedSimpleComponentFactory[clientConfig, clientConfig]{
+	TypeID: "client",
+	CreateInstanceFunc: func(ctx compcont.Context, config clientConfig) (instance clientConfig, err error) {
+		return config, nil
+	},
+}
+
+func loadConfigs(t *testing.T, s string) (configs []compcont.ComponentConfig) {
+	assert.NoError(t, yaml.Unmarshal([]byte(s), &configs))
+	return
+}
+
+func TestTemplate(t *testing.T) {
+	registry := compcont.NewFactoryRegistry()
+	MustRegister(registry)
+	compcont.MustRegister(registry, clientComp)
+
+	templateYAML := `
+- name: shard_tpl
+  type: std.template
+  config:
+    params:
+      host: { type: string, required: true }
+      db: { type: int, default: 0 }
+      timeout: { type: duration, default: 1s }
+    component:
+      type: client
+      config:
+        url: "redis://${host}:6379/${db}"
+        db: ${db}
+        timeout: ${timeout}
+        debug: true
+`
+	cc := compcont.NewComponentContainer(
+		compcont.WithFactoryRegistry(registry),
+		compcont.WithConfigOverrides(compcont.ConfigOverride{Path: []string{"shard_b", "params", "db"}, Value: "3"}),
+	)
+	err := cc.LoadNamedComponents(loadConfigs(t, templateYAML+`
+- { name: shard_a, template: shard_tpl, params: { host: a.local } }
+- { name: shard_b, template: shard_tpl, params: { host: b.local, timeout: 2s }, config: { debug: false } }
+`))
+	assert.NoError(t, err)
+
+	a, err := compcont.GetComponent[clientConfig](cc, "shard_a")
+	assert.NoError(t, err)
+	assert.Equal(t, clientConfig{URL: "redis://a.local:6379/0", DB: 0, Timeout: time.Second, Debug: true}, a.Instance)
+	b, err := compcont.GetComponent[clientConfig](cc, "shard_b")
+	assert.NoError(t, err)
+	assert.Equal(t, clientConfig{URL: "redis://b.local:6379/3", DB: 3, Timeout: 2 * time.Second, Debug: false}, b.Instance)
+
+	// 模板被引用时不能卸载
+	assert.ErrorIs(t, cc.UnloadNamedComponents([]compcont.ComponentName{"shard_tpl"}, false), compcont.ErrComponentHasDependents)
+
+	// 参数类型错误时指出模板的位置
+	cc = compcont.NewComponentContainer(compcont.WithFactoryRegistry(registry))
+	err = cc.LoadNamedComponents(loadConfigs(t, templateYAML+`
+- { name: shard_c, template: shard_tpl, params: { host: c.local, db: first } }
+`))
+	assert.ErrorIs(t, err, compcont.ErrComponentConfigInvalid)
+	assert.ErrorContains(t, err, "template /shard_tpl for component shard_c failed, param db is invalid")
+
+	// 超出int范围的参数被拒绝
+	cc = compcont.NewComponentContainer(compcont.WithFactoryRegistry(registry))
+	err = cc.LoadNamedComponents(loadConfigs(t, templateYAML+`
+- { name: shard_d, template: shard_tpl, params: { host: d.local, db: 18446744073709551615 } }
+`))
+	assert.ErrorIs(t, err, compcont.ErrComponentConfigInvalid)
+	assert.ErrorContains(t, err, "18446744073709551615 overflows int")
+
+	// 引用未声明的参数在加载模板时即报错
+	cc = compcont.NewComponentContainer(compcont.WithFactoryRegistry(registry))
+	err = cc.LoadNamedComponents(loadConfigs(t, `
+- name: bad_tpl
+  type: std.template
+  config:
+    component: { type: client, config: { url: "${missing}" } }
+`))
+	assert.ErrorContains(t, err, "component.config.url refers to undeclared param missing")
+}
+
+func TestTemplateDependencies(t *testing.T) {
+	registry := compcont.NewFactoryRegistry()
+	MustRegister(registry)
+	compcont.MustRegister(registry, clientComp)
+
+	// 依赖只出现在模板展开后的配置中，同一批次中的模板先加载，展开后再推断依赖并排序
+	configs := loadConfigs(t, `
+- { name: alias, template: alias_tpl, params: { target: shard_z } }
+- { name: shard_z, type: client, config: { url: z } }
+- name: alias_tpl
+  type: std.template
+  config:
+    params: { target: { type: string, required: true } }
+    component: { refer: "${target}" }
+`)
+	for range 20 {
+		cc := compcont.NewComponentContainer(compcont.WithFactoryRegistry(registry))
+		assert.NoError(t, cc.LoadNamedComponents(configs))
+		alias, err := compcont.GetComponent[clientConfig](cc, "alias")
+		assert.NoError(t, err)
+		assert.Equal(t, "z", alias.Instance.URL)
+
+		// 热重载时模板与被引用的组件一同变化
+		reloaded := loadConfigs(t, `
+- { name: alias, template: alias_tpl, params: { target: shard_y } }
+- { name: shard_y, type: client, config: { url: y } }
+- name: alias_tpl
+  type: std.template
+  config:
+    params: { target: { type: string, required: true } }
+    component: { refer: "${target}", config: {} }
+`)
+		assert.NoError(t, cc.(compcont.IReloadableContainer).ReloadNamedComponents(reloaded))
+		alias, err = compcont.GetComponent[clientConfig](cc, "alias")
+		assert.NoError(t, err)
+		assert.Equal(t, "y", alias.Instance.URL)
+	}
+}
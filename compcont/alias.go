@@ -0,0 +1,137 @@
+package compcont
+
+import (
+	"fmt"
+	"log/slog"
+)
+
+// TypeAlias 组件类型的别名，用于在类型改名后保持旧的配置可用
+type TypeAlias struct {
+	Alias      ComponentTypeID // 别名，通常是改名前的类型
+	Target     ComponentTypeID // 别名指向的类型，不能是另一个别名
+	Deprecated bool            // 为true时使用别名的组件在加载时输出警告，提示改用Target
+	Since      string          // 别名被废弃的版本
+}
+
+func (a TypeAlias) String() string {
+	s := fmt.Sprintf("%s -> %s", a.Alias, a.Target)
+	if a.Deprecated {
+		s += " (deprecated"
+		if a.Since != "" {
+			s += " since " + a.Since
+		}
+		s += ")"
+	}
+	return s
+}
+
+// RegisterAlias 在registry中注册类型别名，registry需要实现 IAliasRegistry
+func RegisterAlias(registry IFactoryRegistry, alias TypeAlias) error {
+	r, ok := registry.(IAliasRegistry)
+	if !ok {
+		return fmt.Errorf("%w, registry %T does not support aliases", ErrComponentTypeAliasInvalid, registry)
+	}
+	return r.RegisterAlias(alias)
+}
+
+// GetAlias 获取registry中的类型别名，t不是别名或registry未实现 IAliasRegistry 时ok为false
+func GetAlias(registry IFactoryRegistry, t ComponentTypeID) (alias TypeAlias, ok bool) {
+	if r, isAliasRegistry := registry.(IAliasRegistry); isAliasRegistry {
+		return r.GetAlias(t)
+	}
+	return
+}
+
+// RegisteredAliases 获取registry中所有已注册的类型别名，registry未实现 IAliasRegistry 时为空
+func RegisteredAliases(registry IFactoryRegistry) (aliases []TypeAlias) {
+	if r, ok := registry.(IAliasRegistry); ok {
+		return r.RegisteredAliases()
+	}
+	return
+}
+
+// RegisterAlias implements IAliasRegistry.
+func (c *FactoryRegistry) RegisterAlias(alias TypeAlias) error {
+	if alias.Alias == alias.Target {
+		return fmt.Errorf("%w, alias %s points to itself", ErrComponentTypeAliasInvalid, alias.Alias)
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	// 持有锁时检查，避免并发注册出别名指向别名的链
+	_, targetIsAlias := c.aliases[alias.Target]
+	_, targetIsType := c.factories[alias.Target]
+	if !targetIsAlias && !targetIsType && c.parent != nil {
+		_, targetIsAlias = GetAlias(c.parent, alias.Target)
+	}
+	if targetIsAlias {
+		return fmt.Errorf("%w, target %s of alias %s is also an alias", ErrComponentTypeAliasInvalid, alias.Target, alias.Alias)
+	}
+	for _, registered := range c.aliases {
+		if registered.Target == alias.Alias {
+			return fmt.Errorf("%w, alias %s is the target of alias %s", ErrComponentTypeAliasInvalid, alias.Alias, registered.Alias)
+		}
+	}
+	_, isType := c.factories[alias.Alias]
+	_, isAlias := c.aliases[alias.Alias]
+	if isType || isAlias {
+		return fmt.Errorf("%w, alias: %s", ErrComponentTypeAlreadyRegistered, alias.Alias)
+	}
+	c.aliases[alias.Alias] = alias
+	return nil
+}
+
+// GetAlias implements IAliasRegistry.
+func (c *FactoryRegistry) GetAlias(t ComponentTypeID) (alias TypeAlias, ok bool) {
+	c.mu.RLock()
+	alias, ok = c.aliases[t]
+	_, isType := c.factories[t]
+	c.mu.RUnlock()
+	if !ok && !isType && c.parent != nil {
+		return GetAlias(c.parent, t)
+	}
+	return
+}
+
+// RegisteredAliases implements IAliasRegistry.
+func (c *FactoryRegistry) RegisteredAliases() (aliases []TypeAlias) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	for _, alias := range c.aliases {
+		aliases = append(aliases, alias)
+	}
+	if c.parent != nil {
+		for _, alias := range RegisteredAliases(c.parent) {
+			_, isAlias := c.aliases[alias.Alias]
+			_, isType := c.factories[alias.Alias]
+			if !isAlias && !isType {
+				aliases = append(aliases, alias)
+			}
+		}
+	}
+	return
+}
+
+// 组件使用了被废弃的类型别名时输出警告，指明应当改用的类型
+func (c *ComponentContainer) warnDeprecatedType(config ComponentConfig) {
+	if config.Type == "" {
+		return
+	}
+	alias, ok := GetAlias(c.factoryRegistry, config.Type)
+	if !ok || !alias.Deprecated {
+		return
+	}
+	attrs := []any{
+		slog.Any("component", c.componentPath(config.Name)),
+		slog.String("type", string(alias.Alias)),
+		slog.String("replacement", string(alias.Target)),
+	}
+	if alias.Since != "" {
+		attrs = append(attrs, slog.String("deprecated_since", alias.Since))
+	}
+	if factory, err := c.factoryRegistry.GetFactory(alias.Target); err == nil {
+		if versioned, ok := factory.(IComponentTypeVersion); ok {
+			attrs = append(attrs, slog.String("replacement_version", versioned.TypeVersion()))
+		}
+	}
+	c.logger.Warn("component type is deprecated, use the replacement type instead", attrs...)
+}
@@ -0,0 +1,82 @@
+package compcont
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// 引用路径以该前缀开头时，第一级名称先在当前容器中查找，找不到时沿父容器逐级向上查找，如 ^logger、^infra/db
+const AncestorReferPrefix = "^"
+
+// LookupResult 沿祖先容器查找具名组件的结果
+type LookupResult struct {
+	Name      ComponentName
+	Path      []ComponentName   // 找到的组件的绝对路径
+	Depth     int               // 组件所在容器相对于起始容器的层数，0为起始容器自身
+	Searched  [][]ComponentName // 依次搜索过的容器路径，根容器为空
+	Shadowed  [][]ComponentName // 更远的祖先容器中被遮蔽的同名组件的绝对路径
+	container IComponentContainer
+}
+
+func (r LookupResult) shadowedPaths() (paths []string) {
+	for _, path := range r.Shadowed {
+		paths = append(paths, formatPath(path))
+	}
+	return
+}
+
+// LookupInAncestors 像词法作用域一样查找具名组件：先在container中查找，找不到时沿 GetParent 逐级向上查找，
+// 距离最近的容器中的组件生效，并遮蔽更远的祖先容器中的同名组件
+func LookupInAncestors(container IComponentContainer, name ComponentName) (result LookupResult, err error) {
+	result.Name = name
+	for current, depth := container, 0; current != nil; current, depth = current.GetParent(), depth+1 {
+		path := containerPath(current)
+		result.Searched = append(result.Searched, path)
+		if !slices.Contains(current.LoadedComponentNames(), name) {
+			continue
+		}
+		if result.container == nil {
+			result.container = current
+			result.Path = append(path, name)
+			result.Depth = depth
+		} else {
+			result.Shadowed = append(result.Shadowed, append(path, name))
+		}
+	}
+	if result.container == nil {
+		var searched []string
+		for _, path := range result.Searched {
+			searched = append(searched, formatContainerPath(path))
+		}
+		err = fmt.Errorf("%w, name: %s, searched containers: %s", ErrComponentNameNotFound, name, strings.Join(searched, ", "))
+	}
+	return
+}
+
+// GetComponentInAncestors 与 GetComponent 相同，但找不到时沿祖先容器查找，见 LookupInAncestors
+func GetComponentInAncestors[Instance any](container IComponentContainer, name ComponentName) (ret TypedComponent[Instance], err error) {
+	result, err := LookupInAncestors(container, name)
+	if err != nil {
+		return
+	}
+	return GetComponent[Instance](result.container, name)
+}
+
+// 组件以^引用与自身同名的组件时从父容器开始查找，使组件可以包装祖先容器中的同名组件，例如子容器中的 logger 以 ^logger 为基础增加字段
+func referBase(container IComponentContainer, self ComponentName, refer string) IComponentContainer {
+	if self == "" || container.GetParent() == nil {
+		return container
+	}
+	if first, _, _ := strings.Cut(refer, "/"); first == AncestorReferPrefix+string(self) {
+		return container.GetParent()
+	}
+	return container
+}
+
+func formatContainerPath(path []ComponentName) string {
+	if len(path) == 0 {
+		return "/"
+	}
+	return formatPath(path)
+}
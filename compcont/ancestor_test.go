@@ -0,0 +1,70 @@
+package compcont
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAncestorLookup(t *testing.T) {
+	r := NewFactoryRegistry()
+	MustRegister(r, &TypedSimpleComponentFactory[string, *string]{
+		TypeID: "value",
+		CreateInstanceFunc: func(ctx Context, config string) (instance *string, err error) {
+			return &config, nil
+		},
+	}, &TypedSimpleComponentFactory[[]ComponentConfig, IComponentContainer]{
+		TypeID: "inline",
+		CreateInstanceFunc: func(ctx Context, config []ComponentConfig) (instance IComponentContainer, err error) {
+			instance = NewComponentContainer(WithParentContainer(ctx.Container), WithContext(ctx), WithFactoryRegistry(r))
+			err = instance.LoadNamedComponents(config)
+			return
+		},
+	})
+	cc := NewComponentContainer(WithFactoryRegistry(r))
+	assert.NoError(t, cc.LoadNamedComponents([]ComponentConfig{
+		{Name: "logger", Type: "value", Config: "root"},
+		{Name: "db", Type: "value", Config: "root"},
+		{Name: "a", Type: "inline", Config: []ComponentConfig{
+			// 以^引用自身名称时从父容器开始查找
+			{Name: "logger", Refer: "^logger"},
+			{Name: "b", Type: "inline", Config: []ComponentConfig{
+				{Name: "db", Type: "value", Config: "b"},
+				{Name: "logger_ref", Refer: "^logger"},
+				{Name: "db_ref", Refer: "^db"},
+			}},
+		}},
+	}))
+
+	b, err := GetComponent[IComponentContainer](cc, "a")
+	assert.NoError(t, err)
+	b, err = GetComponent[IComponentContainer](b.Instance, "b")
+	assert.NoError(t, err)
+
+	// 距离最近的容器中的同名组件生效
+	result, err := LookupInAncestors(b.Instance, "logger")
+	assert.NoError(t, err)
+	assert.Equal(t, []ComponentName{"a", "logger"}, result.Path)
+	assert.Equal(t, 1, result.Depth)
+	assert.Equal(t, [][]ComponentName{{"logger"}}, result.Shadowed)
+	assert.Len(t, result.Searched, 3)
+
+	loggerRef, err := GetComponent[*string](b.Instance, "logger_ref")
+	assert.NoError(t, err)
+	assert.Equal(t, "root", *loggerRef.Instance)
+	// a/logger 本身引用了根容器的logger
+	assert.Equal(t, []ComponentName{"logger"}, loggerRef.Context.GetAbsolutePath())
+
+	// 当前容器中的组件遮蔽祖先容器中的同名组件
+	dbRef, err := GetComponent[*string](b.Instance, "db_ref")
+	assert.NoError(t, err)
+	assert.Equal(t, "b", *dbRef.Instance)
+
+	db, err := GetComponentInAncestors[*string](b.Instance, "db")
+	assert.NoError(t, err)
+	assert.Equal(t, "b", *db.Instance)
+
+	_, err = LookupInAncestors(b.Instance, "missing")
+	assert.ErrorIs(t, err, ErrComponentNameNotFound)
+	assert.Contains(t, err.Error(), "/a/b, /a, /")
+}
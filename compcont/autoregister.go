@@ -0,0 +1,15 @@
+//go:build !compcont_noinit
+
+package compcont
+
+// AutoRegister 在各个包的init中调用，将包中的工厂注册到 DefaultFactoryRegistry
+//
+// 使用 compcont_noinit 构建标签时不做任何事，此时导入包不会修改全局状态，需要显式调用各个包的 Register 或 MustRegister
+func AutoRegister(register func(registry IFactoryRegistry)) {
+	register(DefaultFactoryRegistry)
+}
+
+// AutoRegisterModule 在包的init中调用，将模块注册为可在配置中按名称引用，使用 compcont_noinit 构建标签时不做任何事
+func AutoRegisterModule(module *Module) {
+	MustRegisterModule(module)
+}
@@ -0,0 +1,9 @@
+//go:build compcont_noinit
+
+package compcont
+
+// AutoRegister 在 compcont_noinit 构建标签下不注册任何工厂
+func AutoRegister(register func(registry IFactoryRegistry)) {}
+
+// AutoRegisterModule 在 compcont_noinit 构建标签下不注册任何模块
+func AutoRegisterModule(module *Module) {}
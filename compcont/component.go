@@ -16,11 +16,19 @@ func (n ComponentName) Validate() bool {
 }
 
 type ComponentConfig struct {
-	Name   ComponentName   `json:"name" yaml:"name"`     // 组件名称，不填为空值，即匿名组件
-	Type   ComponentTypeID `json:"type" yaml:"type"`     // 组件类型
-	Refer  string          `json:"refer" yaml:"refer"`   // 来自其他组件的引用
-	Deps   []ComponentName `json:"deps" yaml:"deps"`     // 构造该组件需要依赖的其他组件名称
-	Config any             `json:"config" yaml:"config"` // 组件的自身配置
+	Name        ComponentName     `json:"name" yaml:"name"`               // 组件名称，不填为空值，即匿名组件
+	Type        ComponentTypeID   `json:"type" yaml:"type"`               // 组件类型
+	Refer       string            `json:"refer" yaml:"refer"`             // 来自其他组件的引用，如 db、../db、/infra/db，^logger 沿祖先容器查找
+	Deps        []ComponentName   `json:"deps" yaml:"deps"`               // 构造该组件需要依赖的其他组件名称
+	Config      any               `json:"config" yaml:"config"`           // 组件的自身配置
+	Template    string            `json:"template" yaml:"template"`       // 引用的组件模板路径，与type、refer互斥，见 IComponentTemplate
+	Params      map[string]any    `json:"params" yaml:"params"`           // 实例化模板的参数
+	Lazy        bool              `json:"lazy" yaml:"lazy"`               // 具名组件在首次被获取时才实例化，加载时只校验依赖、类型和配置
+	Scope       ComponentScope    `json:"scope" yaml:"scope"`             // 具名组件的生命周期，默认为singleton
+	Enabled     *bool             `json:"enabled" yaml:"enabled"`         // 为false时具名组件不会被加载，不填为启用
+	When        string            `json:"when" yaml:"when"`               // expr-lang表达式，可使用env、profile、hostname，求值为false时具名组件不会被加载
+	Labels      map[string]string `json:"labels" yaml:"labels"`           // 标签，可以通过 Selector 按标签查找组件
+	Annotations map[string]string `json:"annotations" yaml:"annotations"` // 注解，供工具使用的附加信息，不参与查找
 }
 
 // 运行时的组件的结构
@@ -34,6 +42,7 @@ type Context struct {
 	Container IComponentContainer // 当前组件所在容器
 	Config    ComponentConfig     // 组件配置
 	Mount     *Component          // 组件实例有可能不存在
+	Scope     *Scope              // 在scope中创建scoped或transient组件时不为空，组件的依赖也在该scope中获取
 }
 
 func (c *Context) FindRoot() Context {
@@ -0,0 +1,162 @@
+package compcont
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+
+	"github.com/expr-lang/expr"
+)
+
+// 未通过 WithProfile 指定时，从该环境变量读取当前的profile
+const ProfileEnvName = "COMPCONT_PROFILE"
+
+// when表达式的求值环境
+type conditionEnv struct {
+	Env      map[string]string `expr:"env"`      // 环境变量
+	Profile  string            `expr:"profile"`  // 当前的profile
+	Hostname string            `expr:"hostname"` // 主机名
+}
+
+func newConditionEnv(profile string) conditionEnv {
+	env := conditionEnv{
+		Env:     make(map[string]string),
+		Profile: profile,
+	}
+	for _, kv := range os.Environ() {
+		if k, v, ok := strings.Cut(kv, "="); ok {
+			env.Env[k] = v
+		}
+	}
+	env.Hostname, _ = os.Hostname()
+	return env
+}
+
+// 判断组件是否启用，enabled为false或when表达式求值为false时组件被禁用
+func isComponentEnabled(config ComponentConfig, env conditionEnv) (enabled bool, err error) {
+	if config.Enabled != nil && !*config.Enabled {
+		return
+	}
+	if config.When == "" {
+		enabled = true
+		return
+	}
+	program, err := expr.Compile(config.When, expr.Env(conditionEnv{}), expr.AsBool())
+	if err != nil {
+		err = fmt.Errorf("%w, component %s has invalid when expression %q, %w", ErrComponentConfigInvalid, config.Name, config.When, err)
+		return
+	}
+	result, err := expr.Run(program, env)
+	if err != nil {
+		err = fmt.Errorf("%w, evaluate when expression %q of component %s failed, %w", ErrComponentConfigInvalid, config.When, config.Name, err)
+		return
+	}
+	enabled = result.(bool)
+	return
+}
+
+// 过滤掉被禁用的组件，启用的组件依赖被禁用的组件时报错
+func (c *ComponentContainer) filterEnabled(configs []ComponentConfig) (enabled []ComponentConfig, disabled set[ComponentName], err error) {
+	env := newConditionEnv(c.profile)
+	disabled = make(set[ComponentName])
+	for _, cfg := range configs {
+		ok, err1 := isComponentEnabled(cfg, env)
+		if err1 != nil {
+			err = err1
+			return
+		}
+		if ok {
+			enabled = append(enabled, cfg)
+		} else {
+			disabled[cfg.Name] = struct{}{}
+		}
+	}
+	if len(disabled) > 0 {
+		c.logger.Info("components are disabled", slog.Any("container", containerPath(c)), slog.Any("components", sortedNames(disabled)))
+	}
+
+	c.mu.RLock()
+	previouslyDisabled := make(set[ComponentName])
+	for name := range c.disabled {
+		previouslyDisabled[name] = struct{}{}
+	}
+	c.mu.RUnlock()
+	for _, cfg := range enabled {
+		delete(previouslyDisabled, cfg.Name)
+	}
+	// 没有被禁用的组件时无需推断依赖，避免重复解码配置
+	if len(disabled) == 0 && len(previouslyDisabled) == 0 {
+		return
+	}
+
+	for _, cfg := range enabled {
+		deps := inferDeps(c.factoryRegistry, cfg)
+		for _, dep := range cfg.Deps {
+			if local, ok := localDependency(string(dep), 0); ok {
+				deps[local] = struct{}{}
+			}
+		}
+		for dep := range deps {
+			_, isDisabled := disabled[dep]
+			_, wasDisabled := previouslyDisabled[dep]
+			if isDisabled || wasDisabled {
+				err = fmt.Errorf("%w, component %s depends on disabled component %s", ErrComponentDisabled, cfg.Name, dep)
+				return
+			}
+		}
+	}
+	return
+}
+
+// 记录被禁用的组件，获取时给出明确的错误，replace为true时以本次的结果替换全部记录
+func (c *ComponentContainer) setDisabled(enabled []ComponentConfig, disabled set[ComponentName], replace bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if replace {
+		c.disabled = make(set[ComponentName])
+	}
+	for _, cfg := range enabled {
+		delete(c.disabled, cfg.Name)
+	}
+	for name := range disabled {
+		c.disabled[name] = struct{}{}
+	}
+}
+
+// Profile 容器当前的profile
+func (c *ComponentContainer) Profile() string {
+	return c.profile
+}
+
+// WithProfile 指定when表达式中的profile，不指定时继承父容器的profile，根容器默认读取环境变量COMPCONT_PROFILE
+func WithProfile(profile string) optionsFunc {
+	return func(o *options) {
+		o.profile = &profile
+	}
+}
+
+// ProfileFlag 通过命令行参数指定profile，如 flag.Var(&profile, "profile", "config profile")，
+// 未在命令行中指定时 Option 不生效，仍按 WithProfile 的规则继承父容器或读取环境变量
+type ProfileFlag struct {
+	value string
+	set   bool
+}
+
+func (f *ProfileFlag) String() string {
+	return f.value
+}
+
+func (f *ProfileFlag) Set(s string) error {
+	f.value = s
+	f.set = true
+	return nil
+}
+
+// Option 命令行中指定了profile时返回 WithProfile，否则返回不做任何修改的选项
+func (f *ProfileFlag) Option() optionsFunc {
+	if !f.set {
+		return func(o *options) {}
+	}
+	return WithProfile(f.value)
+}
@@ -0,0 +1,58 @@
+package compcont
+
+import (
+	"flag"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConditionalComponents(t *testing.T) {
+	t.Setenv("COMPCONT_TEST_DEBUG", "1")
+	disabled := false
+	cc := NewComponentContainer(WithFactoryRegistry(newReloadRegistry()), WithProfile("prod"))
+	assert.NoError(t, cc.LoadNamedComponents([]ComponentConfig{
+		{Name: "a", Type: "reload", Config: "a"},
+		{Name: "pprof", Type: "reload", Config: "pprof", When: `profile == "dev"`},
+		{Name: "debug", Type: "reload", Config: "debug", When: `env["COMPCONT_TEST_DEBUG"] == "1" && hostname != ""`},
+		{Name: "off", Type: "reload", Config: "off", Enabled: &disabled},
+	}))
+	assert.ElementsMatch(t, []ComponentName{"a", "debug"}, cc.LoadedComponentNames())
+
+	_, err := cc.GetComponent("pprof")
+	assert.ErrorIs(t, err, ErrComponentNameNotFound)
+	assert.ErrorIs(t, err, ErrComponentDisabled)
+
+	// 依赖被禁用的组件时报错
+	err = cc.LoadNamedComponents([]ComponentConfig{{Name: "b", Type: "reload", Deps: []ComponentName{"off"}, Config: "b"}})
+	assert.ErrorIs(t, err, ErrComponentDisabled)
+	err = cc.LoadNamedComponents([]ComponentConfig{
+		{Name: "c", Type: "reload", Config: "c", When: `profile == "dev"`},
+		{Name: "d", Type: "reload", Deps: []ComponentName{"c"}, Config: "d"},
+	})
+	assert.ErrorIs(t, err, ErrComponentDisabled)
+
+	err = cc.LoadNamedComponents([]ComponentConfig{{Name: "e", Type: "reload", Config: "e", When: `profile +`}})
+	assert.ErrorIs(t, err, ErrComponentConfigInvalid)
+
+	// 子容器继承profile
+	child := NewComponentContainer(WithParentContainer(cc), WithFactoryRegistry(newReloadRegistry()))
+	assert.NoError(t, child.LoadNamedComponents([]ComponentConfig{{Name: "p", Type: "reload", Config: "p", When: `profile == "prod"`}}))
+	assert.Equal(t, []ComponentName{"p"}, child.LoadedComponentNames())
+}
+
+func TestProfileFlag(t *testing.T) {
+	t.Setenv(ProfileEnvName, "staging")
+
+	var profile ProfileFlag
+	flags := flag.NewFlagSet("test", flag.ContinueOnError)
+	flags.Var(&profile, "profile", "config profile")
+	assert.NoError(t, flags.Parse(nil))
+	cc := NewComponentContainer(profile.Option())
+	assert.Equal(t, "staging", cc.(*ComponentContainer).Profile())
+
+	// 命令行参数优先于环境变量
+	assert.NoError(t, flags.Parse([]string{"-profile", "dev"}))
+	cc = NewComponentContainer(profile.Option())
+	assert.Equal(t, "dev", cc.(*ComponentContainer).Profile())
+}
@@ -0,0 +1,252 @@
+package compcont
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+)
+
+var (
+	contextType   = reflect.TypeFor[Context]()
+	containerType = reflect.TypeFor[IComponentContainer]()
+	errorType     = reflect.TypeFor[error]()
+)
+
+type constructorParamKind int
+
+const (
+	constructorParamContext   constructorParamKind = iota // 注入组件上下文
+	constructorParamContainer                             // 注入组件所在容器
+	constructorParamConfig                                // 注入解码后的组件配置
+	constructorParamDep                                   // 从依赖组件中注入
+)
+
+type constructorParam struct {
+	kind constructorParamKind
+	typ  reflect.Type
+	name ComponentName // 依赖参数指定的组件名，为空则按类型匹配
+}
+
+// ConstructorFactory 基于反射的组件工厂，将一个普通的Go构造函数适配为组件工厂
+//
+// 构造函数形如 func(cfg Config, logger *zap.Logger, rdb compcontredis.Component) (Svc, error)：
+//   - 类型为 Context 或 IComponentContainer 的参数注入当前组件上下文或所在容器
+//   - 第一个参数若不是接口、指针、函数或通道，则视为组件配置，由容器解码后注入
+//   - 其余参数均视为依赖，从组件声明的 deps 中按名称或按类型查找后注入
+//   - 返回值为 (instance) 或 (instance, error)，若实例实现了 io.Closer 则销毁时调用 Close
+type ConstructorFactory struct {
+	typeID ComponentTypeID
+	fn     reflect.Value
+	params []constructorParam
+}
+
+// NewConstructorFactory 根据构造函数创建组件工厂，depNames按顺序指定依赖参数对应的组件名，为空字符串或未指定的依赖参数按类型匹配
+func NewConstructorFactory(typeID ComponentTypeID, fn any, depNames ...ComponentName) (f *ConstructorFactory, err error) {
+	fnValue := reflect.ValueOf(fn)
+	if fnValue.Kind() != reflect.Func || fnValue.IsNil() {
+		err = fmt.Errorf("%w, constructor of %s must be a non-nil function, but got %T", ErrConstructorInvalid, typeID, fn)
+		return
+	}
+	fnType := fnValue.Type()
+	if fnType.IsVariadic() {
+		err = fmt.Errorf("%w, constructor of %s must not be variadic", ErrConstructorInvalid, typeID)
+		return
+	}
+
+	// 校验返回值
+	switch fnType.NumOut() {
+	case 1:
+	case 2:
+		if fnType.Out(1) != errorType {
+			err = fmt.Errorf("%w, the second result of constructor %s must be error", ErrConstructorInvalid, typeID)
+			return
+		}
+	default:
+		err = fmt.Errorf("%w, constructor of %s must return (instance) or (instance, error)", ErrConstructorInvalid, typeID)
+		return
+	}
+
+	// 对参数进行分类
+	var params []constructorParam
+	depIndex := 0
+	for i := range fnType.NumIn() {
+		t := fnType.In(i)
+		switch {
+		case t == contextType:
+			params = append(params, constructorParam{kind: constructorParamContext, typ: t})
+		case t == containerType:
+			params = append(params, constructorParam{kind: constructorParamContainer, typ: t})
+		case i == 0 && isConstructorConfigType(t):
+			params = append(params, constructorParam{kind: constructorParamConfig, typ: t})
+		case isConstructorConfigType(t):
+			err = fmt.Errorf("%w, parameter %d of constructor %s has type %v, only the first parameter can be config", ErrConstructorInvalid, i, typeID, t)
+			return
+		default:
+			param := constructorParam{kind: constructorParamDep, typ: t}
+			if depIndex < len(depNames) {
+				param.name = depNames[depIndex]
+			}
+			depIndex++
+			params = append(params, param)
+		}
+	}
+	if len(depNames) > depIndex {
+		err = fmt.Errorf("%w, constructor of %s has %d dependency parameters, but %d names given", ErrConstructorInvalid, typeID, depIndex, len(depNames))
+		return
+	}
+
+	f = &ConstructorFactory{
+		typeID: typeID,
+		fn:     fnValue,
+		params: params,
+	}
+	return
+}
+
+// 接口、指针、函数、通道类型的参数被视为依赖，其余类型可以作为配置
+func isConstructorConfigType(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Interface, reflect.Pointer, reflect.Func, reflect.Chan:
+		return false
+	default:
+		return true
+	}
+}
+
+// Type implements IComponentFactory.
+func (f *ConstructorFactory) Type() ComponentTypeID {
+	return f.typeID
+}
+
+// CreateInstance implements IComponentFactory.
+func (f *ConstructorFactory) CreateInstance(ctx Context, config any) (instance any, err error) {
+	args := make([]reflect.Value, len(f.params))
+	for i, param := range f.params {
+		switch param.kind {
+		case constructorParamContext:
+			args[i] = reflect.ValueOf(ctx)
+		case constructorParamContainer:
+			args[i] = reflect.New(containerType).Elem()
+			if ctx.Container != nil {
+				args[i].Set(reflect.ValueOf(ctx.Container))
+			}
+		case constructorParamConfig:
+			args[i], err = decodeConstructorConfig(config, param.typ)
+			if err != nil {
+				return
+			}
+		case constructorParamDep:
+			args[i], err = resolveConstructorDep(ctx, param)
+			if err != nil {
+				return
+			}
+		}
+	}
+
+	results := f.fn.Call(args)
+	if len(results) == 2 && !results[1].IsNil() {
+		err = results[1].Interface().(error)
+		return
+	}
+	instance = results[0].Interface()
+	return
+}
+
+// DecodeConfig implements IComponentConfigDecoder.
+func (f *ConstructorFactory) DecodeConfig(rawConfig any) (config any, err error) {
+	for _, param := range f.params {
+		if param.kind != constructorParamConfig {
+			continue
+		}
+		cfg, err := decodeConstructorConfig(rawConfig, param.typ)
+		if err != nil {
+			return nil, err
+		}
+		return cfg.Interface(), nil
+	}
+	return rawConfig, nil
+}
+
+// DestroyInstance implements IComponentFactory.
+func (f *ConstructorFactory) DestroyInstance(ctx Context, instance any) (err error) {
+	if closer, ok := instance.(io.Closer); ok {
+		return closer.Close()
+	}
+	return
+}
+
+func decodeConstructorConfig(rawConfig any, typ reflect.Type) (cfg reflect.Value, err error) {
+	cfgPtr := reflect.New(typ)
+	switch v := rawConfig.(type) {
+	case nil:
+	case map[string]any:
+		err = decodeMapConfig(v, cfgPtr.Interface())
+		if err != nil {
+			return
+		}
+	default:
+		rv := reflect.ValueOf(rawConfig)
+		if !rv.Type().AssignableTo(typ) {
+			err = fmt.Errorf("unexpected config type %v, expected %v", rv.Type(), typ)
+			return
+		}
+		cfgPtr.Elem().Set(rv)
+	}
+	cfg = cfgPtr.Elem()
+	return
+}
+
+// 从组件声明的依赖中解析构造函数参数，指定了名称则按名称获取，否则在deps中查找唯一一个类型匹配的组件
+func resolveConstructorDep(ctx Context, param constructorParam) (arg reflect.Value, err error) {
+	if param.name != "" {
+		var component Component
+		component, err = resolveReferInScope(referBase(ctx.Container, ctx.Config.Name, string(param.name)), string(param.name), ctx.Scope)
+		if err != nil {
+			return
+		}
+		if component.Instance == nil || !reflect.TypeOf(component.Instance).AssignableTo(param.typ) {
+			err = fmt.Errorf("%w, name: %s, expected instance type %v, but got %T", ErrComponentTypeMismatch, param.name, param.typ, component.Instance)
+			return
+		}
+		arg = reflect.ValueOf(component.Instance)
+		return
+	}
+
+	var matched []ComponentName
+	for _, dep := range ctx.Config.Deps {
+		component, err1 := resolveReferInScope(referBase(ctx.Container, ctx.Config.Name, string(dep)), string(dep), ctx.Scope)
+		if err1 != nil {
+			err = err1
+			return
+		}
+		if component.Instance == nil || !reflect.TypeOf(component.Instance).AssignableTo(param.typ) {
+			continue
+		}
+		matched = append(matched, dep)
+		arg = reflect.ValueOf(component.Instance)
+	}
+	switch len(matched) {
+	case 0:
+		err = fmt.Errorf("%w, no dependency of %s is assignable to %v", ErrComponentDependencyNotFound, ctx.Config.Name, param.typ)
+	case 1:
+	default:
+		err = fmt.Errorf("%w, dependencies %v of %s are all assignable to %v", ErrComponentDependencyAmbiguous, matched, ctx.Config.Name, param.typ)
+	}
+	return
+}
+
+// RegisterConstructor 将一个构造函数注册为组件类型，参见 ConstructorFactory
+func RegisterConstructor(registry IFactoryRegistry, typeID ComponentTypeID, fn any, depNames ...ComponentName) error {
+	f, err := NewConstructorFactory(typeID, fn, depNames...)
+	if err != nil {
+		return err
+	}
+	return registry.Register(f)
+}
+
+func MustRegisterConstructor(registry IFactoryRegistry, typeID ComponentTypeID, fn any, depNames ...ComponentName) {
+	err := RegisterConstructor(registry, typeID, fn, depNames...)
+	if err != nil {
+		panic(err)
+	}
+}
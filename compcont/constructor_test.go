@@ -0,0 +1,72 @@
+package compcont
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type greeterConfig struct {
+	Greeting string `ccf:"greeting"`
+}
+
+type greeter struct {
+	greeting string
+	a        IComponentA
+	closed   bool
+}
+
+func (g *greeter) Close() error {
+	g.closed = true
+	return nil
+}
+
+func TestRegisterConstructor(t *testing.T) {
+	r := NewFactoryRegistry()
+	MustRegister(r, factoryA)
+	MustRegisterConstructor(r, "greeter", func(cfg greeterConfig, a IComponentA) (*greeter, error) {
+		if cfg.Greeting == "" {
+			return nil, errors.New("greeting is required")
+		}
+		return &greeter{greeting: cfg.Greeting, a: a}, nil
+	})
+	MustRegisterConstructor(r, "named-greeter", func(ctx Context, a IComponentA) *greeter {
+		return &greeter{greeting: string(ctx.Config.Name), a: a}
+	}, "a2")
+
+	cc := NewComponentContainer(WithFactoryRegistry(r))
+	err := cc.LoadNamedComponents([]ComponentConfig{
+		{Name: "a1", Type: "a", Config: map[string]any{"test_a": "a1"}},
+		{Name: "a2", Type: "a", Config: map[string]any{"test_a": "a2"}},
+		{Name: "g1", Type: "greeter", Deps: []ComponentName{"a1"}, Config: map[string]any{"greeting": "hello"}},
+		{Name: "g2", Type: "named-greeter", Deps: []ComponentName{"a1", "a2"}},
+	})
+	assert.NoError(t, err)
+
+	g1, err := GetComponent[*greeter](cc, "g1")
+	assert.NoError(t, err)
+	assert.Equal(t, "hello", g1.Instance.greeting)
+	assert.Equal(t, "a1", g1.Instance.a.GetConfigA().TestA)
+
+	g2, err := GetComponent[*greeter](cc, "g2")
+	assert.NoError(t, err)
+	assert.Equal(t, "g2", g2.Instance.greeting)
+	assert.Equal(t, "a2", g2.Instance.a.GetConfigA().TestA)
+
+	f, err := r.GetFactory("greeter")
+	assert.NoError(t, err)
+	assert.NoError(t, f.DestroyInstance(g1.Context, g1.Instance))
+	assert.True(t, g1.Instance.closed)
+
+	// 按类型匹配到多个依赖时报错
+	_, err = cc.LoadAnonymousComponent(ComponentConfig{Type: "greeter", Deps: []ComponentName{"a1", "a2"}, Config: greeterConfig{Greeting: "hi"}})
+	assert.ErrorIs(t, err, ErrComponentDependencyAmbiguous)
+
+	// 构造函数自身返回的错误
+	_, err = cc.LoadAnonymousComponent(ComponentConfig{Type: "greeter", Deps: []ComponentName{"a1"}})
+	assert.EqualError(t, err, "greeting is required")
+
+	_, err = NewConstructorFactory("invalid", func(a IComponentA, cfg greeterConfig) *greeter { return nil })
+	assert.ErrorIs(t, err, ErrConstructorInvalid)
+}
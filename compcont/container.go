@@ -1,14 +1,72 @@
 package compcont
 
+import "context"
+
 // 组件的容器抽象
 type IComponentContainer interface {
 	GetContext() Context                                                            // 当容器自身作为组件时的组件上下文对象
 	FactoryRegistry() IFactoryRegistry                                              // 该组件容器所使用的组件工厂注册器
-	LoadedComponentNames() (names []ComponentName)                                  // 获取所有已加载的组件名
+	LoadedComponentNames() (names []ComponentName)                                  // 获取所有已加载的组件名，按名称排序
 	LoadNamedComponents(configs []ComponentConfig) error                            // 实例化一批组件，内部自动基于拓扑排序的顺序完成组件的实例化
-	UnloadNamedComponents(name []ComponentName, recursive bool) error               // 卸载一批组件，若指定recursive则递归地卸载依赖组件
+	UnloadNamedComponents(name []ComponentName, recursive bool) error               // 卸载一批组件，若指定recursive则递归地卸载依赖于这些组件的组件
 	LoadAnonymousComponent(config ComponentConfig) (component Component, err error) // 立即加载一个匿名的组件
 	GetComponent(name ComponentName) (component Component, err error)               // 获取一个已加载的具名组件
 	PutComponent(name ComponentName, component Component) (err error)               // 直接放入一个组件
 	GetParent() IComponentContainer                                                 // 如果是根容器，则返回nil
 }
+
+// 可选的容器接口，监听容器及其子孙容器的生命周期事件
+type IEventContainer interface {
+	AddEventListener(listener EventListener) (id int) // 添加生命周期事件监听器，同时会收到子孙容器的事件
+	RemoveEventListener(id int)                       // 移除生命周期事件监听器
+}
+
+// 可选的容器接口，获取容器及其子容器中全部具名组件的只读快照
+type ISnapshotContainer interface {
+	Snapshot() (snapshot ContainerSnapshot)
+}
+
+// 可选的容器接口，以新的完整组件配置热重载，只重建发生变化的组件及依赖它们的组件，失败时回滚
+type IReloadableContainer interface {
+	ReloadNamedComponents(configs []ComponentConfig) error
+}
+
+// 可选的容器接口，以当前配置重建指定组件及依赖它们的组件，失败时回滚
+type IRebuildableContainer interface {
+	RebuildComponents(names []ComponentName) error
+}
+
+// 可选的容器接口，限制组件对容器外部的可见性，未实现时全部组件可见，见 WithExports
+type IExportingContainer interface {
+	Exported(name ComponentName) bool
+}
+
+// 可选的容器接口，重新读取容器自身的配置来源并以其热重载，如从配置文件或reloading源加载组件的子容器
+type ISourceContainer interface {
+	ReloadSource(ctx context.Context) error
+}
+
+// 包装了其他容器的容器，如附带了配置源的子容器组件实例，获取句柄等由具体容器实现提供的能力时会通过Unwrap找到被包装的容器
+type IContainerWrapper interface {
+	Unwrap() IComponentContainer
+}
+
+// UnwrapContainer 沿 IContainerWrapper 找到第一个实现了T的容器，用于获取 IEventContainer 等可选接口
+func UnwrapContainer[T any](container IComponentContainer) (impl T, ok bool) {
+	return unwrapContainer[T](container)
+}
+
+// 沿 IContainerWrapper 找到第一个实现了T的容器
+func unwrapContainer[T any](container IComponentContainer) (impl T, ok bool) {
+	for container != nil {
+		if impl, ok = container.(T); ok {
+			return
+		}
+		wrapper, isWrapper := container.(IContainerWrapper)
+		if !isWrapper {
+			return
+		}
+		container = wrapper.Unwrap()
+	}
+	return
+}
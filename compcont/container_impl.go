@@ -1,17 +1,75 @@
 package compcont
 
 import (
+	"errors"
 	"fmt"
+	"log/slog"
+	"os"
+	"reflect"
+	"slices"
 	"strings"
 	"sync"
+	"time"
 )
 
 type ComponentContainer struct {
-	context         Context
-	parent          IComponentContainer
-	factoryRegistry IFactoryRegistry
-	components      map[ComponentName]Component
-	mu              sync.RWMutex
+	context           Context
+	parent            IComponentContainer
+	factoryRegistry   IFactoryRegistry
+	components        map[ComponentName]Component
+	configs           map[ComponentName]ComponentConfig                  // 通过配置加载的具名组件的配置，用于热重载时比较差异
+	statuses          map[ComponentName]*componentStatus                 // 具名组件的生命周期状态，包括加载失败的组件
+	dependencies      map[ComponentName]set[componentKey]                // 组件依赖了哪些组件，可跨容器
+	dependents        map[ComponentName]set[componentKey]                // 组件被哪些组件所依赖，可跨容器
+	handles           map[ComponentName]map[reflect.Type]componentHandle // 通过 GetHandle 获取的句柄，热重载时切换到新实例
+	handleGracePeriod time.Duration
+	logger            *slog.Logger
+	profiler          *Profiler
+	profile           string
+	disabled          set[ComponentName]    // 因enabled或when被禁用的组件
+	overrides         []ConfigOverride      // 配置覆盖项，加载组件前应用
+	appliedOverrides  *appliedOverrides     // 整个容器树中已经应用过的覆盖项
+	listeners         map[int]EventListener // 生命周期事件监听器
+	nextListenerID    int
+	exports           set[ComponentName] // 对容器外部可见的组件，nil表示全部可见
+	modules           []ModuleSnapshot   // 通过 LoadModule 加载到容器中的模块
+	mu                sync.RWMutex
+	reloadMu          sync.Mutex // 保证同一时刻只有一个热重载在进行
+}
+
+// 由所在容器和名称唯一确定的一个具名组件
+type componentKey struct {
+	container IComponentContainer
+	name      ComponentName
+}
+
+// 记录from组件依赖to组件，匿名组件不记录
+func recordDependency(from Context, to Context) {
+	if from.Config.Name == "" || to.Config.Name == "" {
+		return
+	}
+	fromKey := componentKey{container: from.Container, name: from.Config.Name}
+	toKey := componentKey{container: to.Container, name: to.Config.Name}
+	if fromKey == toKey {
+		return
+	}
+	if source, ok := from.Container.(*ComponentContainer); ok {
+		source.mu.Lock()
+		addEdge(source.dependencies, fromKey.name, toKey)
+		source.mu.Unlock()
+	}
+	if target, ok := to.Container.(*ComponentContainer); ok {
+		target.mu.Lock()
+		addEdge(target.dependents, toKey.name, fromKey)
+		target.mu.Unlock()
+	}
+}
+
+func addEdge(edges map[ComponentName]set[componentKey], name ComponentName, key componentKey) {
+	if _, ok := edges[name]; !ok {
+		edges[name] = make(set[componentKey])
+	}
+	edges[name][key] = struct{}{}
 }
 
 // GetSelfComponentName implements IComponentContainer.
@@ -26,13 +84,29 @@ func (c *ComponentContainer) GetParent() IComponentContainer {
 
 // GetComponentMetadata implements IComponentContainer.
 func (c *ComponentContainer) GetComponent(name ComponentName) (component Component, err error) {
+	return c.getComponent(name, nil)
+}
+
+// 获取组件，scoped组件必须在scope中获取，transient组件每次获取都会创建新实例，在scope中创建时由scope负责销毁
+func (c *ComponentContainer) getComponent(name ComponentName, scope *Scope) (component Component, err error) {
 	c.mu.RLock()
-	defer c.mu.RUnlock()
 	inner, ok := c.components[name]
+	_, disabled := c.disabled[name]
+	c.mu.RUnlock()
 	if !ok {
+		if disabled {
+			err = fmt.Errorf("%w, %w, name: %s", ErrComponentNameNotFound, ErrComponentDisabled, name)
+			return
+		}
 		err = fmt.Errorf("%w, name: %s", ErrComponentNameNotFound, name)
 		return
 	}
+	if isLazyPlaceholder(inner) {
+		return c.instantiateLazy(name, inner)
+	}
+	if isScopedPlaceholder(inner) {
+		return c.getScopedComponent(inner.Context.Config, scope)
+	}
 	component = inner
 	return
 }
@@ -42,44 +116,50 @@ func (c *ComponentContainer) FactoryRegistry() IFactoryRegistry {
 	return c.factoryRegistry
 }
 
-func (c *ComponentContainer) loadComponent(config ComponentConfig) (component Component, err error) {
+// decoded为排序时已解码的配置，为空时在此解码
+func (c *ComponentContainer) loadComponent(config ComponentConfig, scope *Scope, decoded *decodedConfig) (component Component, err error) {
+	profile := ComponentProfile{Path: c.componentPath(config.Name), TypeID: config.Type, Begin: time.Now()}
+	var depContexts []Context
+	// 构造期间通过Ref、Collection等记录的依赖关系在加载失败时需要移除
+	if config.Name != "" {
+		recorded := c.dependencyKeys(config.Name)
+		defer func() {
+			if err != nil {
+				c.removeDependenciesExcept(config.Name, recorded)
+			}
+		}()
+	}
+	c.emit(Event{Type: EventComponentLoading, Path: profile.Path, TypeID: config.Type})
+	defer func() {
+		event := Event{Type: EventComponentLoaded, Path: profile.Path, TypeID: config.Type, Duration: time.Since(profile.Begin), Err: err}
+		if err != nil {
+			event.Type = EventComponentFailed
+		}
+		c.emit(event)
+		if c.profiler != nil {
+			profile.End = time.Now()
+			profile.Deps = c.profileDeps(config.Name, depContexts)
+			profile.Err = err
+			c.profiler.record(profile)
+		}
+	}()
 	if config.Type == "" {
 		if config.Refer == "" { // 引用组件
 			err = fmt.Errorf("%w, type && refer are empty", ErrComponentConfigInvalid)
 			return
 		}
-		parts := strings.Split(config.Refer, "/")
-		absolute := false
-		if parts[0] == "" { // 绝对路径
-			absolute = true
-			parts = parts[1:]
-		}
-
-		var findPath []ComponentName
-		for _, p := range parts {
-
-			if n := ComponentName(p); p != "." && p != ".." && !n.Validate() {
-				err = fmt.Errorf("%w, in refer %s", ErrComponentNameInvalid, config.Refer)
-				return
-			} else {
-				findPath = append(findPath, n)
-			}
-		}
-
-		// 寻找到要引用的树节点，再从对应节点上获取组件
-		var ctx Context
-		ctx, err = find(c, findPath, absolute)
+		component, err = resolveReferInScope(referBase(c, config.Name, config.Refer), config.Refer, scope)
 		if err != nil {
 			return
 		}
-		return ctx.Container.GetComponent(ctx.Config.Name)
+		depContexts = append(depContexts, component.Context)
+		recordDependency(Context{Container: c, Config: config}, component.Context)
+		return
 	}
-	// 检查依赖关系是否满足
-	for _, dep := range config.Deps {
-		if _, ok := c.components[dep]; !ok {
-			err = fmt.Errorf("%w, dependency %s not found", ErrComponentDependencyNotFound, dep)
-			return
-		}
+	// 检查依赖关系是否满足，依赖实例由工厂在使用时获取
+	depContexts, err = c.checkDeps(config)
+	if err != nil {
+		return
 	}
 
 	// 获取工厂
@@ -91,10 +171,25 @@ func (c *ComponentContainer) loadComponent(config ComponentConfig) (component Co
 	ctx := Context{
 		Config:    config,
 		Container: c,
+		Scope:     scope,
+	}
+
+	// 解码配置，工厂支持时单独解码以便统计耗时，复用排序时的解码结果时统计当时的耗时
+	rawConfig := config.Config
+	if _, ok := factory.(IComponentConfigDecoder); ok {
+		if !decoded.reusable(config) {
+			decoded = decodeComponentConfig(c.factoryRegistry, config)
+		}
+		profile.Decode = decoded.duration
+		if rawConfig, err = decoded.config, decoded.err; err != nil {
+			return
+		}
 	}
 
 	// 构造组件实例
-	instance, err := factory.CreateInstance(ctx, config.Config)
+	phaseStart := time.Now()
+	instance, err := factory.CreateInstance(ctx, rawConfig)
+	profile.Create = time.Since(phaseStart)
 	if err != nil {
 		return
 	}
@@ -103,12 +198,52 @@ func (c *ComponentContainer) loadComponent(config ComponentConfig) (component Co
 	component = Component{Instance: instance}
 	ctx.Mount = &component
 	component.Context = ctx
+
+	// 启动组件，启动失败时销毁已创建的实例
+	if starter, ok := factory.(IComponentStarter); ok {
+		phaseStart = time.Now()
+		err = starter.StartInstance(ctx, instance)
+		profile.Start = time.Since(phaseStart)
+		if err != nil {
+			err = fmt.Errorf("start component failed, %w", err)
+			if err1 := factory.DestroyInstance(ctx, instance); err1 != nil {
+				err = errors.Join(err, fmt.Errorf("destroy component failed, %w", err1))
+			}
+			component = Component{}
+			return
+		}
+	}
+
+	for _, depCtx := range depContexts {
+		recordDependency(ctx, depCtx)
+	}
+	// 从配置推断出的依赖同样需要记录，以便卸载和热重载时能找到受影响的组件
+	if config.Name != "" {
+		for dep := range scanConfig(c.factoryRegistry, config, decoded).deps {
+			if dep != config.Name && c.isLoaded(dep) {
+				recordDependency(ctx, Context{Container: c, Config: ComponentConfig{Name: dep}})
+			}
+		}
+	}
 	return
 }
 
 // LoadAnonymousComponent 加载一个匿名组件，返回该组件实例，生命周期不由Registry控制，需要由该方法的调用方自行处理
 func (c *ComponentContainer) LoadAnonymousComponent(config ComponentConfig) (component Component, err error) {
-	return c.loadComponent(config)
+	// 被禁用的匿名组件返回 ErrComponentDisabled，由调用方决定是否跳过
+	enabled, err := isComponentEnabled(config, newConditionEnv(c.profile))
+	if err != nil {
+		return
+	}
+	if !enabled {
+		err = fmt.Errorf("%w, type: %s", ErrComponentDisabled, config.Type)
+		return
+	}
+	if config, _, err = c.expandTemplate(config); err != nil {
+		return
+	}
+	c.warnDeprecatedType(config)
+	return c.loadComponent(config, nil, nil)
 }
 
 // PutComponent implements IComponentContainer.
@@ -121,60 +256,496 @@ func (c *ComponentContainer) PutComponent(name ComponentName, component Componen
 
 // LoadNamedComponents 加载一批具名组件，内部会自行根据拓扑排序顺序加载组件
 func (c *ComponentContainer) LoadNamedComponents(configs []ComponentConfig) (err error) {
+	start := time.Now()
+	c.emit(Event{Type: EventContainerLoading, Path: containerPath(c)})
+	defer func() {
+		c.emit(Event{Type: EventContainerLoaded, Path: containerPath(c), Duration: time.Since(start), Err: err})
+	}()
+	configs, err = c.applyOverrides(configs)
+	if err != nil {
+		return
+	}
+	configs, disabled, err := c.filterEnabled(configs)
+	if err != nil {
+		return
+	}
+	c.setDisabled(configs, disabled, false)
+
+	// 组件的顺序加载器，TODO 可以实现组件的并发启动优化
+	_, err = c.loadSorted(configs, c.isLoaded, c.loadNamedComponent)
+	if err == nil && c.parent == nil {
+		c.warnUnmatchedOverrides()
+	}
+	return
+}
+
+// 检查声明的依赖是否存在，依赖可以是同容器的组件名，也可以是其他容器中组件的引用路径，
+// 只检查是否存在，不触发懒加载、scoped和transient组件的实例化
+func (c *ComponentContainer) checkDeps(config ComponentConfig) (depContexts []Context, err error) {
+	for _, dep := range config.Deps {
+		if dep.Validate() { // 同容器的依赖记录为对该名称的依赖，它可能是对其他组件的引用
+			if !c.isLoaded(dep) {
+				err = fmt.Errorf("%w, dependency %s not found", ErrComponentDependencyNotFound, dep)
+				return
+			}
+			depContexts = append(depContexts, Context{Container: c, Config: ComponentConfig{Name: dep}})
+			continue
+		}
+		depCtx, err1 := findReferTarget(referBase(c, config.Name, string(dep)), string(dep))
+		if err1 != nil {
+			err = fmt.Errorf("%w, dependency %s not found, %w", ErrComponentDependencyNotFound, dep, err1)
+			return
+		}
+		depContexts = append(depContexts, depCtx)
+	}
+	return
+}
+
+func (c *ComponentContainer) isLoaded(name ComponentName) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	_, ok := c.components[name]
+	return ok
+}
+
+// 加载一个具名组件并放入容器，decoded为排序时按展开后的配置解码的结果
+func (c *ComponentContainer) loadNamedComponent(config ComponentConfig, decoded *decodedConfig) (err error) {
+	start := time.Now()
+	c.setStatus(config.Name, func(status *componentStatus) {
+		status.state = StateCreating
+		status.config = config
+	})
+	defer func() {
+		c.setStatus(config.Name, func(status *componentStatus) {
+			status.duration = time.Since(start)
+			if err != nil {
+				status.state = StateFailed
+				status.lastErr = err
+				return
+			}
+			status.state = StateReady
+			status.loadedAt = time.Now()
+		})
+	}()
+
+	expanded, template, err := c.expandTemplate(config)
+	if err != nil {
+		return
+	}
+	c.setStatus(config.Name, func(status *componentStatus) { status.config = expanded })
+	c.warnDeprecatedType(expanded)
+	if err = c.loadExpandedComponent(expanded, decoded); err != nil {
+		return
+	}
+	// 记录展开前的配置，热重载时与新配置比较
+	c.mu.Lock()
+	c.configs[config.Name] = config
+	c.mu.Unlock()
+	if config.Template != "" {
+		recordDependency(Context{Container: c, Config: ComponentConfig{Name: config.Name}}, template.Context)
+	}
+	return
+}
+
+func (c *ComponentContainer) loadExpandedComponent(config ComponentConfig, decoded *decodedConfig) (err error) {
+	if config.Type != "" {
+		switch config.Scope {
+		case ScopeSingleton, "":
+		case ScopeTransient, ScopeScoped:
+			return c.loadDeferredComponent(config, &scopedComponent{}, decoded)
+		default:
+			return fmt.Errorf("%w, component %s has unknown scope %s", ErrComponentConfigInvalid, config.Name, config.Scope)
+		}
+		if config.Lazy {
+			return c.loadDeferredComponent(config, &lazyComponent{}, decoded)
+		}
+	}
+	component, err := c.loadComponent(config, nil, decoded)
+	if err != nil {
+		return
+	}
+	c.mu.Lock()
+	c.components[config.Name] = component
+	c.mu.Unlock()
+	return
+}
+
+// 校验组件名称并对一批组件进行拓扑排序，existing用于判断依赖是否已经存在于容器中，已存在的依赖不参与排序，
+// decoded为推断依赖时解码的各组件配置，加载时复用
+func (c *ComponentContainer) sortComponents(configs []ComponentConfig, existing func(name ComponentName) bool) (configMap map[ComponentName]ComponentConfig, orders []ComponentName, decoded map[ComponentName]*decodedConfig, err error) {
 	// 校验组件名称并构造map
-	configMap := make(map[ComponentName]ComponentConfig)
+	configMap = make(map[ComponentName]ComponentConfig)
 	for _, cfg := range configs {
 		if !cfg.Name.Validate() {
-			return fmt.Errorf("%w, name: %s", ErrComponentNameInvalid, cfg.Name)
+			err = fmt.Errorf("%w, name: %s", ErrComponentNameInvalid, cfg.Name)
+			return
 		}
 		configMap[cfg.Name] = cfg
 	}
 
-	// 拓扑排序
-	var orders []ComponentName
-	{
-		// 构建组件依赖图
-		dag := make(map[ComponentName]set[ComponentName])
-		for _, cfg := range configs {
-			name := cfg.Name
-			if _, ok := dag[name]; !ok {
-				dag[name] = make(map[ComponentName]struct{})
-			}
-			for _, dep := range cfg.Deps {
-				// 已存在的依赖关系则不加入本次的DAG构建
-				c.mu.RLock()
-				_, ok := c.components[dep]
-				c.mu.RUnlock()
-				if ok {
+	// 构建组件依赖图，除了显式声明的deps外，还会加入从配置中推断出的隐式依赖
+	dag := make(map[ComponentName]set[ComponentName])
+	decoded = make(map[ComponentName]*decodedConfig)
+	for _, cfg := range configs {
+		name := cfg.Name
+		if _, ok := dag[name]; !ok {
+			dag[name] = make(map[ComponentName]struct{})
+		}
+		// 引用模板的组件按展开后的配置推断依赖，模板无法展开时按原配置推断，错误留到组件构造时报告
+		scanCfg, unexpanded := cfg, false
+		if cfg.Template != "" {
+			if expanded, _, err := c.expandTemplate(cfg); err == nil {
+				scanCfg = expanded
+			} else {
+				unexpanded = true
+			}
+		}
+		scanned := scanConfig(c.factoryRegistry, scanCfg, nil)
+		if scanned.decoded != nil {
+			decoded[name] = scanned.decoded
+		}
+		inferred := scanned.deps
+		// 以^引用自身名称时指向祖先容器中的同名组件，不构成自依赖
+		delete(inferred, name)
+		// 构造函数组件的依赖通过参数注入，不会出现在配置中
+		factory, _ := c.factoryRegistry.GetFactory(scanCfg.Type)
+		_, injected := factory.(*ConstructorFactory)
+		for _, dep := range scanCfg.Deps {
+			local, ok := localDependency(string(dep), 0)
+			if !ok {
+				// 其他容器中的依赖需要已经加载完成，在组件构造时检查
+				continue
+			}
+			if _, inBatch := configMap[local]; strings.HasPrefix(string(dep), AncestorReferPrefix) && (!inBatch || local == name) {
+				// 本批次中没有该名称时沿祖先容器查找，在组件构造时检查
+				continue
+			}
+			// 收集组件时声明的deps用于保证被收集的组件先加载，不会出现在配置中
+			collecting := len(scanned.collectors) > 0
+			if _, ok := inferred[local]; !ok && !injected && !collecting && !unexpanded && local == dep {
+				c.logger.Warn("declared dependency is not referenced in component config",
+					slog.String("component", string(name)),
+					slog.String("dependency", string(dep)),
+				)
+			}
+			// 已存在的依赖关系则不加入本次的DAG构建
+			if existing(local) {
+				continue
+			}
+			dag[cfg.Name][local] = struct{}{}
+		}
+		for dep := range inferred {
+			if _, ok := dag[name][dep]; ok || slices.Contains(scanCfg.Deps, dep) {
+				continue
+			}
+			loaded := existing(dep)
+			_, inBatch := configMap[dep]
+			if !loaded && !inBatch {
+				// 既不在本批次也未加载，留到组件构造时报错
+				continue
+			}
+			c.logger.Warn("dependency referenced in component config is not declared in deps",
+				slog.String("component", string(name)),
+				slog.String("dependency", string(dep)),
+			)
+			if inBatch && !loaded {
+				dag[name][dep] = struct{}{}
+			}
+		}
+		// 按标签收集组件时，本批次中标签满足选择器的组件需要先加载
+		for _, collector := range scanned.collectors {
+			selector, exclude, _ := collector.collects()
+			if selector.Empty() {
+				continue
+			}
+			for other, otherCfg := range configMap {
+				if other == name || slices.Contains(exclude, other) || existing(other) || !selector.Matches(otherCfg.Labels) {
 					continue
 				}
-				dag[cfg.Name][dep] = struct{}{}
+				dag[name][other] = struct{}{}
 			}
 		}
+	}
+
+	// 对新组件集合进行拓扑排序
+	orders, err = topologicalSort(dag)
+	return
+}
 
-		// 对新组件集合进行拓扑排序
-		orders, err = topologicalSort(dag)
+// 按依赖顺序加载一批组件，返回已加载的组件名称，出错时返回出错前已加载的组件
+//
+// 本批次中被引用的模板及其依赖会先单独排序加载，引用模板的组件在排序时才能展开配置并推断依赖
+func (c *ComponentContainer) loadSorted(configs []ComponentConfig, existing func(name ComponentName) bool, load func(config ComponentConfig, decoded *decodedConfig) error) (loaded []ComponentName, err error) {
+	if first, rest := splitTemplates(c.factoryRegistry, configs); len(first) > 0 {
+		if loaded, err = c.loadSorted(first, existing, load); err != nil {
+			return
+		}
+		configs = rest
+	}
+	configMap, orders, decoded, err := c.sortComponents(configs, func(name ComponentName) bool {
+		return existing(name) || slices.Contains(loaded, name)
+	})
+	if err != nil {
+		return
+	}
+	for _, name := range orders {
+		if err = load(configMap[name], decoded[name]); err != nil {
+			return
+		}
+		loaded = append(loaded, name)
+	}
+	return
+}
+
+// 拆分出本批次中被其他组件以template引用的模板组件，以及它们直接或间接依赖的本批次组件
+func splitTemplates(registry IFactoryRegistry, configs []ComponentConfig) (first, rest []ComponentConfig) {
+	configMap := make(map[ComponentName]ComponentConfig, len(configs))
+	for _, cfg := range configs {
+		configMap[cfg.Name] = cfg
+	}
+	var queue []ComponentName
+	for _, cfg := range configs {
+		if cfg.Template == "" {
+			continue
+		}
+		if name, ok := localDependency(cfg.Template, 0); ok && name != cfg.Name {
+			if _, inBatch := configMap[name]; inBatch {
+				queue = append(queue, name)
+			}
+		}
+	}
+	required := make(set[ComponentName])
+	for len(queue) > 0 {
+		name := queue[0]
+		queue = queue[1:]
+		if _, ok := required[name]; ok {
+			continue
+		}
+		required[name] = struct{}{}
+		cfg := configMap[name]
+		deps := inferDeps(registry, cfg)
+		for _, dep := range cfg.Deps {
+			if local, ok := localDependency(string(dep), 0); ok {
+				deps[local] = struct{}{}
+			}
+		}
+		for dep := range deps {
+			if _, inBatch := configMap[dep]; inBatch && dep != name {
+				queue = append(queue, dep)
+			}
+		}
+	}
+	// 全部组件都需要先加载时无法拆分，如模板之间存在循环依赖，由排序报告错误
+	if len(required) == 0 || len(required) == len(configs) {
+		return nil, configs
+	}
+	for _, cfg := range configs {
+		if _, ok := required[cfg.Name]; ok {
+			first = append(first, cfg)
+		} else {
+			rest = append(rest, cfg)
+		}
+	}
+	return
+}
+
+// UnloadNamedComponents 卸载一批具名组件，组件仍被其他组件(可能在其他容器中)依赖时，
+// 若指定recursive则先递归地卸载这些依赖组件，否则报错。卸载创建失败的组件会清除其失败状态
+func (c *ComponentContainer) UnloadNamedComponents(names []ComponentName, recursive bool) (err error) {
+	failed := make(set[ComponentName])
+	for _, name := range names {
+		c.mu.RLock()
+		_, ok := c.components[name]
+		c.mu.RUnlock()
+		if !ok && c.isFailed(name) {
+			failed[name] = struct{}{}
+			continue
+		}
+		if !ok {
+			return fmt.Errorf("%w, name: %s", ErrComponentNameNotFound, name)
+		}
+	}
+	c.clearFailed(func(name ComponentName) bool {
+		_, ok := failed[name]
+		return ok
+	})
+	batch := make(set[ComponentName])
+	for _, name := range names {
+		batch[name] = struct{}{}
+	}
+	for _, name := range names {
+		err = c.unloadComponent(name, recursive, batch)
 		if err != nil {
 			return
 		}
 	}
+	return
+}
 
-	// 组件的顺序加载器，TODO 可以实现组件的并发启动优化
-	for _, name := range orders {
-		component, err := c.loadComponent(configMap[name])
+// 卸载单个组件，batch为本次一同卸载的组件，即使未指定recursive也会先卸载其中依赖该组件的组件
+func (c *ComponentContainer) unloadComponent(name ComponentName, recursive bool, batch set[ComponentName]) (err error) {
+	c.mu.RLock()
+	component, ok := c.components[name]
+	var dependents []componentKey
+	for key := range c.dependents[name] {
+		dependents = append(dependents, key)
+	}
+	c.mu.RUnlock()
+	if !ok { // 已经在递归卸载中被卸载
+		return
+	}
+
+	// 先卸载依赖该组件的组件
+	if !recursive {
+		var paths [][]ComponentName
+		for _, key := range dependents {
+			if _, ok := batch[key.name]; ok && key.container == IComponentContainer(c) {
+				continue
+			}
+			paths = append(paths, (&Context{Container: key.container, Config: ComponentConfig{Name: key.name}}).GetAbsolutePath())
+		}
+		if len(paths) > 0 {
+			return fmt.Errorf("%w, component %s is required by %v", ErrComponentHasDependents, name, paths)
+		}
+	}
+	for _, key := range dependents {
+		if key.container == IComponentContainer(c) {
+			err = c.unloadComponent(key.name, recursive, batch)
+		} else {
+			err = unloadIfLoaded(key.container, key.name)
+		}
 		if err != nil {
-			return err
+			return
+		}
+	}
+
+	// 销毁前先让句柄不再指向该实例，卸载不会等待通过句柄获取的使用者release
+	c.mu.Lock()
+	for _, h := range c.handles[name] {
+		h.remove()
+	}
+	delete(c.handles, name)
+	c.mu.Unlock()
+
+	c.setStatus(name, func(status *componentStatus) { status.state = StateDestroying })
+	err = c.destroyComponent(name, component)
+	if err != nil {
+		c.setStatus(name, func(status *componentStatus) {
+			status.state = StateReady
+			status.lastErr = err
+		})
+		return
+	}
+
+	// 移除组件及其依赖关系
+	c.mu.Lock()
+	delete(c.components, name)
+	delete(c.configs, name)
+	delete(c.statuses, name)
+	c.mu.Unlock()
+	c.removeEdges(name)
+	return
+}
+
+// 销毁组件实例，引用其他组件得到的组件由被引用组件的所在容器负责销毁，这里不做处理
+func (c *ComponentContainer) destroyComponent(name ComponentName, component Component) (err error) {
+	start := time.Now()
+	typeID := component.Context.Config.Type
+	c.emit(Event{Type: EventComponentUnloading, Path: c.componentPath(name), TypeID: typeID})
+	defer func() {
+		c.emit(Event{Type: EventComponentUnloaded, Path: c.componentPath(name), TypeID: typeID, Duration: time.Since(start), Err: err})
+	}()
+	owned := component.Context.Container == IComponentContainer(c) && component.Context.Config.Name == name
+	if !owned || component.Context.Config.Type == "" || isLazyPlaceholder(component) || isScopedPlaceholder(component) {
+		return
+	}
+	factory, err := c.factoryRegistry.GetFactory(component.Context.Config.Type)
+	if err != nil {
+		return
+	}
+	return factory.DestroyInstance(component.Context, component.Instance)
+}
+
+// 移除组件的全部依赖关系，返回被移除的依赖关系
+func (c *ComponentContainer) removeEdges(name ComponentName) (dependencies, dependents set[componentKey]) {
+	self := componentKey{container: c, name: name}
+	c.mu.Lock()
+	dependencies = c.dependencies[name]
+	dependents = c.dependents[name]
+	delete(c.dependencies, name)
+	delete(c.dependents, name)
+	c.mu.Unlock()
+	for key := range dependencies {
+		if target, ok := key.container.(*ComponentContainer); ok {
+			target.mu.Lock()
+			delete(target.dependents[key.name], self)
+			target.mu.Unlock()
 		}
-		c.mu.Lock()
-		c.components[name] = component
-		c.mu.Unlock()
 	}
 	return
 }
 
-// UnloadNamedComponents implements IComponentRegistry.
-func (c *ComponentContainer) UnloadNamedComponents(name []ComponentName, recursive bool) error {
-	panic("unimplemented")
+// 组件当前依赖的组件
+func (c *ComponentContainer) dependencyKeys(name ComponentName) (keys set[componentKey]) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	keys = make(set[componentKey])
+	for key := range c.dependencies[name] {
+		keys[key] = struct{}{}
+	}
+	return
+}
+
+// 移除组件不在kept中的依赖关系
+func (c *ComponentContainer) removeDependenciesExcept(name ComponentName, kept set[componentKey]) {
+	self := componentKey{container: c, name: name}
+	var removed []componentKey
+	c.mu.Lock()
+	for key := range c.dependencies[name] {
+		if _, ok := kept[key]; !ok {
+			delete(c.dependencies[name], key)
+			removed = append(removed, key)
+		}
+	}
+	if len(c.dependencies[name]) == 0 {
+		delete(c.dependencies, name)
+	}
+	c.mu.Unlock()
+	for _, key := range removed {
+		if target, ok := key.container.(*ComponentContainer); ok {
+			target.mu.Lock()
+			delete(target.dependents[key.name], self)
+			target.mu.Unlock()
+		}
+	}
+}
+
+// 恢复被 removeEdges 移除的依赖关系
+func (c *ComponentContainer) restoreEdges(name ComponentName, dependencies, dependents set[componentKey]) {
+	self := componentKey{container: c, name: name}
+	c.mu.Lock()
+	if len(dependencies) > 0 {
+		c.dependencies[name] = dependencies
+	}
+	if len(dependents) > 0 {
+		c.dependents[name] = dependents
+	}
+	c.mu.Unlock()
+	for key := range dependencies {
+		if target, ok := key.container.(*ComponentContainer); ok {
+			target.mu.Lock()
+			addEdge(target.dependents, key.name, self)
+			target.mu.Unlock()
+		}
+	}
+}
+
+// 卸载其他容器中的组件，组件已被卸载时忽略
+func unloadIfLoaded(container IComponentContainer, name ComponentName) error {
+	if _, err := container.GetComponent(name); err != nil {
+		return nil
+	}
+	return container.UnloadNamedComponents([]ComponentName{name}, true)
 }
 
 // LoadedComponentNames implements IComponentRegistry.
@@ -184,13 +755,20 @@ func (c *ComponentContainer) LoadedComponentNames() (names []ComponentName) {
 	for t := range c.components {
 		names = append(names, t)
 	}
+	slices.Sort(names)
 	return
 }
 
 type options struct {
-	factoryRegistry IFactoryRegistry
-	parent          IComponentContainer
-	context         Context
+	factoryRegistry   IFactoryRegistry
+	parent            IComponentContainer
+	context           Context
+	logger            *slog.Logger
+	handleGracePeriod time.Duration
+	profiler          *Profiler
+	profile           *string
+	overrides         []ConfigOverride
+	exports           set[ComponentName]
 }
 
 type optionsFunc func(o *options)
@@ -213,6 +791,13 @@ func WithContext(ctx Context) optionsFunc {
 	}
 }
 
+// WithLogger 指定容器输出诊断信息使用的日志，不指定时继承父容器的日志，根容器默认使用slog.Default()
+func WithLogger(logger *slog.Logger) optionsFunc {
+	return func(o *options) {
+		o.logger = logger
+	}
+}
+
 func NewComponentContainer(optFns ...optionsFunc) (cr IComponentContainer) {
 	var opt options
 	for _, fn := range optFns {
@@ -222,9 +807,60 @@ func NewComponentContainer(optFns ...optionsFunc) (cr IComponentContainer) {
 	if opt.factoryRegistry == nil {
 		opt.factoryRegistry = DefaultFactoryRegistry
 	}
+	if opt.handleGracePeriod == 0 {
+		if parent, ok := opt.parent.(*ComponentContainer); ok {
+			opt.handleGracePeriod = parent.handleGracePeriod
+		} else {
+			opt.handleGracePeriod = DefaultHandleGracePeriod
+		}
+	}
+	if opt.logger == nil {
+		if parent, ok := opt.parent.(*ComponentContainer); ok {
+			opt.logger = parent.logger
+		} else {
+			opt.logger = slog.Default()
+		}
+	}
+	if opt.profile == nil {
+		profile := os.Getenv(ProfileEnvName)
+		if parent, ok := opt.parent.(*ComponentContainer); ok {
+			profile = parent.profile
+		}
+		opt.profile = &profile
+	}
+	// 子容器继承父容器的覆盖项，显式指定的覆盖项最后生效
+	var overrides []ConfigOverride
+	if parent, ok := opt.parent.(*ComponentContainer); ok {
+		overrides = append(overrides, parent.overrides...)
+	}
+	opt.overrides = append(overrides, opt.overrides...)
+	applied := &appliedOverrides{keys: make(set[string]), reported: make(set[string])}
+	if parent, ok := opt.parent.(*ComponentContainer); ok {
+		applied = parent.appliedOverrides
+	}
+	if opt.profiler == nil {
+		if parent, ok := opt.parent.(*ComponentContainer); ok {
+			opt.profiler = parent.profiler
+		}
+	}
 	return &ComponentContainer{
-		factoryRegistry: opt.factoryRegistry,
-		parent:          opt.parent,
-		components:      make(map[ComponentName]Component),
+		context:           opt.context,
+		factoryRegistry:   opt.factoryRegistry,
+		parent:            opt.parent,
+		components:        make(map[ComponentName]Component),
+		configs:           make(map[ComponentName]ComponentConfig),
+		statuses:          make(map[ComponentName]*componentStatus),
+		dependencies:      make(map[ComponentName]set[componentKey]),
+		dependents:        make(map[ComponentName]set[componentKey]),
+		handles:           make(map[ComponentName]map[reflect.Type]componentHandle),
+		handleGracePeriod: opt.handleGracePeriod,
+		logger:            opt.logger,
+		profiler:          opt.profiler,
+		profile:           *opt.profile,
+		disabled:          make(set[ComponentName]),
+		overrides:         opt.overrides,
+		appliedOverrides:  applied,
+		listeners:         make(map[int]EventListener),
+		exports:           opt.exports,
 	}
 }
@@ -1,6 +1,9 @@
 package compcont
 
 import (
+	"bytes"
+	"errors"
+	"log/slog"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -64,10 +67,10 @@ var factoryB = &TypedSimpleComponentFactory[ConfigB, IComponentB]{
 }
 
 func Test(t *testing.T) {
-	DefaultFactoryRegistry.Register(factoryA)
-	DefaultFactoryRegistry.Register(factoryB)
+	r := NewFactoryRegistry()
+	MustRegister(r, factoryA, factoryB)
 
-	registry := NewComponentContainer()
+	registry := NewComponentContainer(WithFactoryRegistry(r))
 	err := registry.LoadNamedComponents([]ComponentConfig{
 		(&TypedComponentConfig[ConfigB, IComponentB]{
 			Name: "cb",
@@ -90,3 +93,255 @@ func Test(t *testing.T) {
 
 	assert.Equal(t, "testa", componentB.Instance.GetConfigB().InnerA.Config.TestA)
 }
+
+type ConfigC struct {
+	A Ref[IComponentA] `ccf:"a"`
+}
+
+func TestRef(t *testing.T) {
+	r := NewFactoryRegistry()
+	MustRegister(r, factoryA)
+	MustRegister(r, &TypedSimpleComponentFactory[ConfigC, IComponentA]{
+		TypeID: "c",
+		CreateInstanceFunc: func(ctx Context, config ConfigC) (component IComponentA, err error) {
+			a, err := config.A.Load(ctx)
+			if err != nil {
+				return
+			}
+			component = a.Instance
+			return
+		},
+	})
+
+	cc := NewComponentContainer(WithFactoryRegistry(r))
+	err := cc.LoadNamedComponents([]ComponentConfig{
+		{Name: "a", Type: "a", Config: map[string]any{"test_a": "testa"}},
+		{Name: "c", Type: "c", Deps: []ComponentName{"a"}, Config: map[string]any{"a": "a"}},
+	})
+	assert.NoError(t, err)
+
+	c, err := GetComponent[IComponentA](cc, "c")
+	assert.NoError(t, err)
+	assert.Equal(t, "testa", c.Instance.GetConfigA().TestA)
+
+	_, err = cc.LoadAnonymousComponent(ComponentConfig{Type: "c", Config: ConfigC{A: NewRef[IComponentA]("not_exists")}})
+	assert.ErrorIs(t, err, ErrComponentNameNotFound)
+	// 兼容由 TypedComponentConfig 改为Ref之前的 { refer: path } 形式，依赖同样可以推断
+	err = cc.LoadNamedComponents([]ComponentConfig{
+		{Name: "legacy", Type: "c", Config: map[string]any{"a": map[string]any{"refer": "a"}}},
+	})
+	assert.NoError(t, err)
+	legacy, err := GetComponent[IComponentA](cc, "legacy")
+	assert.NoError(t, err)
+	assert.Same(t, c.Instance, legacy.Instance)
+	for _, component := range cc.(ISnapshotContainer).Snapshot().Components {
+		if component.Path[0] == "legacy" {
+			assert.Equal(t, [][]ComponentName{{"a"}}, component.Dependencies)
+		}
+	}
+
+	// 带有type等字段的内联组件配置无法作为Ref
+	err = cc.LoadNamedComponents([]ComponentConfig{
+		{Name: "inline", Type: "c", Config: map[string]any{"a": map[string]any{"type": "a"}}},
+	})
+	assert.Error(t, err)
+}
+
+func TestRefFailedLoad(t *testing.T) {
+	r := NewFactoryRegistry()
+	MustRegister(r, factoryA)
+	MustRegister(r, &TypedSimpleComponentFactory[ConfigC, IComponentA]{
+		TypeID: "c",
+		CreateInstanceFunc: func(ctx Context, config ConfigC) (component IComponentA, err error) {
+			if _, err = config.A.Load(ctx); err != nil {
+				return
+			}
+			err = errors.New("create failed")
+			return
+		},
+	})
+
+	cc := NewComponentContainer(WithFactoryRegistry(r))
+	assert.NoError(t, cc.LoadNamedComponents([]ComponentConfig{{Name: "a", Type: "a"}}))
+	err := cc.LoadNamedComponents([]ComponentConfig{{Name: "c", Type: "c", Config: map[string]any{"a": "a"}}})
+	assert.Error(t, err)
+
+	// 加载失败的组件不再被记录为依赖方
+	assert.NoError(t, cc.UnloadNamedComponents([]ComponentName{"a"}, false))
+}
+
+func TestReferPath(t *testing.T) {
+	r := NewFactoryRegistry()
+	MustRegister(r, factoryA)
+
+	root := NewComponentContainer(WithFactoryRegistry(r))
+	child := NewComponentContainer(WithFactoryRegistry(r), WithParentContainer(root), WithContext(Context{Config: ComponentConfig{Name: "c1"}}))
+	assert.NoError(t, child.LoadNamedComponents([]ComponentConfig{{Name: "a", Type: "a", Config: ConfigA{TestA: "inner"}}}))
+	assert.NoError(t, root.PutComponent("c1", Component{Context: Context{Container: root, Config: ComponentConfig{Name: "c1"}}, Instance: child}))
+	assert.NoError(t, root.LoadNamedComponents([]ComponentConfig{{Name: "a", Type: "a", Config: ConfigA{TestA: "outer"}}}))
+
+	// 多级路径需要进入子容器查找，而不是在当前容器中查找最后一级名称
+	a, err := NewRef[IComponentA]("/c1/a").Load(Context{Container: root})
+	assert.NoError(t, err)
+	assert.Equal(t, "inner", a.Instance.GetConfigA().TestA)
+	a, err = NewRef[IComponentA]("../a").Load(Context{Container: child})
+	assert.NoError(t, err)
+	assert.Equal(t, "outer", a.Instance.GetConfigA().TestA)
+
+	// 根容器没有父容器
+	_, err = NewRef[IComponentA]("../a").Load(Context{Container: root})
+	assert.ErrorIs(t, err, ErrComponentNameNotFound)
+}
+
+func TestInferDeps(t *testing.T) {
+	r := NewFactoryRegistry()
+	MustRegister(r, factoryA)
+	MustRegister(r, &TypedSimpleComponentFactory[ConfigC, IComponentA]{
+		TypeID: "c",
+		CreateInstanceFunc: func(ctx Context, config ConfigC) (component IComponentA, err error) {
+			a, err := config.A.Load(ctx)
+			if err != nil {
+				return
+			}
+			component = a.Instance
+			return
+		},
+	})
+
+	assert.Equal(t, set[ComponentName]{"a": {}}, inferDeps(r, ComponentConfig{Type: "c", Config: map[string]any{"a": "a"}}))
+	assert.Equal(t, set[ComponentName]{"a": {}, "b": {}}, inferDeps(r, ComponentConfig{Type: "c", Config: map[string]any{
+		"inner": map[string]any{"refer": "a/x"},
+		"components": []any{
+			map[string]any{"name": "x", "refer": "../b", "deps": []any{"y", "../../z"}},
+			map[string]any{"name": "y", "type": "a", "config": map[string]any{"refer": "x"}},
+		},
+	}}))
+	assert.Equal(t, set[ComponentName]{"a": {}}, inferDeps(r, ComponentConfig{Type: "c", Config: ConfigC{A: NewRef[IComponentA]("./a")}}))
+
+	// 未声明deps，依赖关系从配置中推断
+	cc := NewComponentContainer(WithFactoryRegistry(r))
+	err := cc.LoadNamedComponents([]ComponentConfig{
+		{Name: "c", Type: "c", Config: map[string]any{"a": "a"}},
+		{Name: "d", Refer: "c"},
+		{Name: "a", Type: "a", Config: map[string]any{"test_a": "testa"}},
+	})
+	assert.NoError(t, err)
+	d, err := GetComponent[IComponentA](cc, "d")
+	assert.NoError(t, err)
+	assert.Equal(t, "testa", d.Instance.GetConfigA().TestA)
+}
+
+type countingDecoder struct {
+	*TypedSimpleComponentFactory[ConfigC, IComponentA]
+	decoded map[string]int
+}
+
+func (f *countingDecoder) DecodeConfig(rawConfig any) (config any, err error) {
+	f.decoded[rawConfig.(map[string]any)["a"].(string)]++
+	return f.TypedSimpleComponentFactory.DecodeConfig(rawConfig)
+}
+
+func TestDecodeConfigOnce(t *testing.T) {
+	factory := &countingDecoder{decoded: make(map[string]int), TypedSimpleComponentFactory: &TypedSimpleComponentFactory[ConfigC, IComponentA]{
+		TypeID: "c",
+		CreateInstanceFunc: func(ctx Context, config ConfigC) (component IComponentA, err error) {
+			a, err := config.A.Load(ctx)
+			if err != nil {
+				return
+			}
+			component = a.Instance
+			return
+		},
+	}}
+	r := NewFactoryRegistry()
+	MustRegister(r, factoryA, factory)
+
+	// 排序时解码的配置在构造组件和记录推断的依赖时复用
+	cc := NewComponentContainer(WithFactoryRegistry(r))
+	assert.NoError(t, cc.LoadNamedComponents([]ComponentConfig{
+		{Name: "c", Type: "c", Config: map[string]any{"a": "a"}},
+		{Name: "lazy", Type: "c", Lazy: true, Config: map[string]any{"a": "b"}},
+		{Name: "a", Type: "a", Config: map[string]any{"test_a": "testa"}},
+		{Name: "b", Type: "a", Config: map[string]any{"test_a": "testb"}},
+	}))
+	assert.Equal(t, map[string]int{"a": 1, "b": 1}, factory.decoded)
+	// 推断出的依赖已记录
+	assert.ErrorIs(t, cc.UnloadNamedComponents([]ComponentName{"a"}, false), ErrComponentHasDependents)
+	assert.ErrorIs(t, cc.UnloadNamedComponents([]ComponentName{"b"}, false), ErrComponentHasDependents)
+
+	// 懒加载组件在实例化时解码
+	_, err := GetComponent[IComponentA](cc, "lazy")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]int{"a": 1, "b": 2}, factory.decoded)
+}
+
+func TestFindComponentsByType(t *testing.T) {
+	r := NewFactoryRegistry()
+	MustRegister(r, factoryA)
+	MustRegister(r, factoryB)
+
+	cc := NewComponentContainer(WithFactoryRegistry(r))
+	err := cc.LoadNamedComponents([]ComponentConfig{
+		{Name: "a2", Type: "a", Config: ConfigA{TestA: "a2"}},
+		{Name: "a1", Type: "a", Config: ConfigA{TestA: "a1"}},
+		{Name: "a3", Refer: "a1"},
+		{Name: "b", Type: "b", Config: ConfigB{InnerA: TypedComponentConfig[ConfigA, IComponentA]{Type: "a"}}},
+	})
+	assert.NoError(t, err)
+
+	as, err := FindComponentsByType[IComponentA](cc)
+	assert.NoError(t, err)
+	assert.Len(t, as, 2)
+	assert.Equal(t, "a1", as[0].Instance.GetConfigA().TestA)
+	assert.Equal(t, "a2", as[1].Instance.GetConfigA().TestA)
+
+	child := NewComponentContainer(WithFactoryRegistry(r), WithParentContainer(cc))
+	_, err = FindOne[IComponentB](child)
+	assert.ErrorIs(t, err, ErrComponentNameNotFound)
+	b := MustFindOne[IComponentB](child, FindInAncestors())
+	assert.Equal(t, ComponentName("b"), b.Context.Config.Name)
+	_, err = FindOne[IComponentA](child, FindInAncestors())
+	assert.ErrorIs(t, err, ErrComponentDependencyAmbiguous)
+}
+
+type collectConfig struct {
+	Items Collection[string] `ccf:"items"`
+}
+
+func TestCollection(t *testing.T) {
+	r := NewFactoryRegistry()
+	MustRegister(r, &TypedSimpleComponentFactory[string, string]{
+		TypeID: "echo",
+		CreateInstanceFunc: func(ctx Context, config string) (instance string, err error) {
+			return config, nil
+		},
+	}, &TypedSimpleComponentFactory[collectConfig, []string]{
+		TypeID: "collect",
+		CreateInstanceFunc: func(ctx Context, config collectConfig) (instance []string, err error) {
+			items, err := config.Items.Load(ctx)
+			for _, item := range items {
+				instance = append(instance, item.Instance)
+			}
+			return
+		},
+	})
+
+	var logs bytes.Buffer
+	cc := NewComponentContainer(WithFactoryRegistry(r), WithLogger(slog.New(slog.NewTextHandler(&logs, nil))))
+	assert.NoError(t, cc.LoadNamedComponents([]ComponentConfig{
+		// 按选择器收集时，标签匹配的组件先于收集方加载
+		{Name: "a", Type: "collect", Config: map[string]any{"items": map[string]any{"enabled": true, "selector": "role=item"}}},
+		// 未指定选择器时通过deps保证加载顺序
+		{Name: "b", Type: "collect", Deps: []ComponentName{"y", "z"}, Config: map[string]any{"items": map[string]any{"enabled": true, "exclude": []any{"x"}}}},
+		{Name: "x", Type: "echo", Config: "x"},
+		{Name: "y", Type: "echo", Config: "y", Labels: map[string]string{"role": "item"}},
+		{Name: "z", Type: "echo", Config: "z", Labels: map[string]string{"role": "item"}},
+	}))
+	a, err := GetComponent[[]string](cc, "a")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"y", "z"}, a.Instance)
+	b, err := GetComponent[[]string](cc, "b")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"y", "z"}, b.Instance)
+	assert.NotContains(t, logs.String(), "declared dependency is not referenced")
+}
@@ -11,5 +11,16 @@ var (
 	ErrComponentDependencyNotFound    = errors.New("component dependency not found")
 	ErrComponentTypeNotRegistered     = errors.New("component type not registered")
 	ErrComponentTypeAlreadyRegistered = errors.New("component type already registered")
+	ErrComponentTypeNotAllowed        = errors.New("component type not allowed")
+	ErrComponentTypeAliasInvalid      = errors.New("component type alias invalid")
 	ErrCircularDependency             = errors.New("circular dependency detected")
+	ErrComponentDependencyAmbiguous   = errors.New("component dependency is ambiguous")
+	ErrComponentHasDependents         = errors.New("component is required by other components")
+	ErrComponentScopeRequired         = errors.New("scoped component must be got within a scope")
+	ErrScopeClosed                    = errors.New("scope is closed")
+	ErrComponentDisabled              = errors.New("component is disabled")
+	ErrConstructorInvalid             = errors.New("component constructor invalid")
+	ErrModuleNotRegistered            = errors.New("module not registered")
+	ErrModuleAlreadyRegistered        = errors.New("module already registered")
+	ErrComponentNotExported           = errors.New("component is not exported")
 )
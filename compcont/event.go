@@ -0,0 +1,78 @@
+package compcont
+
+import (
+	"time"
+)
+
+type EventType string
+
+const (
+	EventComponentLoading   EventType = "component_loading"   // 组件开始加载
+	EventComponentLoaded    EventType = "component_loaded"    // 组件加载成功
+	EventComponentFailed    EventType = "component_failed"    // 组件加载失败
+	EventComponentUnloading EventType = "component_unloading" // 组件开始卸载
+	EventComponentUnloaded  EventType = "component_unloaded"  // 组件卸载完成，卸载失败时Err不为空
+	EventContainerLoading   EventType = "container_loading"   // 容器开始加载一批具名组件
+	EventContainerLoaded    EventType = "container_loaded"    // 容器加载一批具名组件完成，失败时Err不为空
+	EventContainerReloading EventType = "container_reloading" // 容器开始热重载
+	EventContainerReloaded  EventType = "container_reloaded"  // 容器热重载完成，失败时Err不为空
+)
+
+// 容器生命周期事件
+type Event struct {
+	Type     EventType
+	Path     []ComponentName // 组件的绝对路径，匿名组件的最后一级为空；容器事件为容器自身的路径
+	TypeID   ComponentTypeID // 组件类型，容器事件为空
+	Duration time.Duration   // 已完成事件的耗时
+	Err      error
+}
+
+type EventListener interface {
+	OnEvent(event Event)
+}
+
+type EventListenerFunc func(event Event)
+
+func (f EventListenerFunc) OnEvent(event Event) {
+	f(event)
+}
+
+// AddEventListener 添加事件监听器，监听器会收到该容器及其所有子孙容器的事件，返回的id用于移除监听器
+func (c *ComponentContainer) AddEventListener(listener EventListener) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.nextListenerID++
+	c.listeners[c.nextListenerID] = listener
+	return c.nextListenerID
+}
+
+// RemoveEventListener 移除事件监听器
+func (c *ComponentContainer) RemoveEventListener(id int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.listeners, id)
+}
+
+// 将事件依次发送给当前容器及其祖先容器的监听器
+func (c *ComponentContainer) emit(event Event) {
+	for current := IComponentContainer(c); current != nil; current = current.GetParent() {
+		cc, ok := current.(*ComponentContainer)
+		if !ok {
+			continue
+		}
+		cc.mu.RLock()
+		listeners := make([]EventListener, 0, len(cc.listeners))
+		for _, listener := range cc.listeners {
+			listeners = append(listeners, listener)
+		}
+		cc.mu.RUnlock()
+		for _, listener := range listeners {
+			listener.OnEvent(event)
+		}
+	}
+}
+
+// 组件事件的路径
+func (c *ComponentContainer) componentPath(name ComponentName) []ComponentName {
+	return append(containerPath(c), name)
+}
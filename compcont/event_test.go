@@ -0,0 +1,53 @@
+package compcont
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEventListener(t *testing.T) {
+	cc := NewComponentContainer(WithFactoryRegistry(newReloadRegistry()))
+	var (
+		mu     sync.Mutex
+		events []Event
+	)
+	id := cc.(IEventContainer).AddEventListener(EventListenerFunc(func(event Event) {
+		mu.Lock()
+		defer mu.Unlock()
+		events = append(events, event)
+	}))
+	types := func() (ret []EventType) {
+		mu.Lock()
+		defer mu.Unlock()
+		for _, event := range events {
+			ret = append(ret, event.Type)
+		}
+		events = nil
+		return
+	}
+
+	assert.NoError(t, cc.LoadNamedComponents([]ComponentConfig{{Name: "a", Type: "reload", Config: "a1"}}))
+	assert.Equal(t, []EventType{EventContainerLoading, EventComponentLoading, EventComponentLoaded, EventContainerLoaded}, types())
+
+	assert.Error(t, cc.LoadNamedComponents([]ComponentConfig{{Name: "b", Type: "reload", Config: "fail"}}))
+	mu.Lock()
+	assert.Equal(t, []ComponentName{"b"}, events[2].Path)
+	assert.Equal(t, ComponentTypeID("reload"), events[2].TypeID)
+	assert.Error(t, events[2].Err)
+	mu.Unlock()
+	assert.Equal(t, []EventType{EventContainerLoading, EventComponentLoading, EventComponentFailed, EventContainerLoaded}, types())
+
+	assert.NoError(t, cc.(IReloadableContainer).ReloadNamedComponents([]ComponentConfig{{Name: "a", Type: "reload", Config: "a2"}}))
+	assert.Equal(t, []EventType{
+		EventContainerReloading,
+		EventComponentLoading, EventComponentLoaded,
+		EventComponentUnloading, EventComponentUnloaded,
+		EventContainerReloaded,
+	}, types())
+
+	cc.(IEventContainer).RemoveEventListener(id)
+	assert.NoError(t, cc.UnloadNamedComponents([]ComponentName{"a"}, false))
+	assert.Empty(t, types())
+}
@@ -0,0 +1,68 @@
+package compcont
+
+import (
+	"fmt"
+	"slices"
+)
+
+// WithExports 声明子容器对外可见的组件，父容器和兄弟容器中的组件只能通过refer、deps等引用路径访问这些组件，
+// 其余组件只在子容器及其子孙容器内部可见。exports为nil时全部组件对外可见，为空列表时不对外暴露任何组件
+func WithExports(exports []ComponentName) optionsFunc {
+	return func(o *options) {
+		if exports == nil {
+			o.exports = nil
+			return
+		}
+		o.exports = make(set[ComponentName], len(exports))
+		for _, name := range exports {
+			o.exports[name] = struct{}{}
+		}
+	}
+}
+
+// Exported implements IExportingContainer.
+func (c *ComponentContainer) Exported(name ComponentName) bool {
+	if c.exports == nil {
+		return true
+	}
+	_, ok := c.exports[name]
+	return ok
+}
+
+// CheckExports 在加载子容器的组件之前检查exports均为configs中声明的组件，避免exports中的拼写错误使组件被意外隐藏，
+// exports为nil时不做检查
+func CheckExports(exports []ComponentName, configs []ComponentConfig) (err error) {
+	declared := make(set[ComponentName], len(configs))
+	for _, config := range configs {
+		declared[config.Name] = struct{}{}
+	}
+	var missing []ComponentName
+	for _, name := range exports {
+		if _, ok := declared[name]; !ok {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		err = fmt.Errorf("%w, exported components %v are not declared", ErrComponentConfigInvalid, missing)
+	}
+	return
+}
+
+// 组件是否对容器外部可见，容器未实现 IExportingContainer 时全部组件可见
+func isExported(container IComponentContainer, name ComponentName) bool {
+	exporting, ok := unwrapContainer[IExportingContainer](container)
+	return !ok || exporting.Exported(name)
+}
+
+// 从origin出发的引用路径进入container后访问其中的name，container是origin自身或其祖先时不受exports限制
+func checkExported(origin, container IComponentContainer, name ComponentName) (err error) {
+	if isExported(container, name) {
+		return
+	}
+	path, originPath := containerPath(container), containerPath(origin)
+	if len(path) <= len(originPath) && slices.Equal(path, originPath[:len(path)]) {
+		return
+	}
+	err = fmt.Errorf("%w, component %s is not exported by container %s", ErrComponentNotExported, name, formatContainerPath(path))
+	return
+}
@@ -1,15 +1,53 @@
 package compcont
 
+import "fmt"
+
 // 组件工厂的抽象
 type IFactoryRegistry interface {
 	Register(f IComponentFactory) error                            // 注册组件工厂
 	Unregister(t ComponentTypeID) error                            // 取消注册组件工厂
 	RegisteredComponentTypes() (types []ComponentTypeID)           // 获取所有已注册的组件工厂
-	GetFactory(t ComponentTypeID) (f IComponentFactory, err error) // 根据组件类型获取组件工厂
+	GetFactory(t ComponentTypeID) (f IComponentFactory, err error) // 根据组件类型获取组件工厂，别名会被解析为目标类型的工厂
+}
+
+// 可选的注册器接口，支持组件类型的别名，通过 RegisterAlias 等函数使用
+type IAliasRegistry interface {
+	RegisterAlias(alias TypeAlias) error                   // 注册组件类型的别名
+	GetAlias(t ComponentTypeID) (alias TypeAlias, ok bool) // 获取类型别名，t不是别名时ok为false
+	RegisteredAliases() (aliases []TypeAlias)              // 获取所有已注册的类型别名
+}
+
+// 可选的组件工厂接口，将原始配置解码为工厂的具体配置类型，容器在推断依赖等场景下使用
+type IComponentConfigDecoder interface {
+	DecodeConfig(rawConfig any) (config any, err error)
+}
+
+// 可选的组件工厂接口，提供组件类型的版本，用于在类型迁移时给出提示
+type IComponentTypeVersion interface {
+	TypeVersion() string
+}
+
+// 可选的组件工厂接口，实例创建完成后由容器调用以启动组件，启动失败时实例会被销毁
+type IComponentStarter interface {
+	StartInstance(ctx Context, instance any) (err error)
+}
+
+// Register 将一组工厂注册到registry，任意一个注册失败时撤销本次已注册的工厂
+func Register(registry IFactoryRegistry, factories ...IComponentFactory) (err error) {
+	for i, factory := range factories {
+		if err = registry.Register(factory); err != nil {
+			err = fmt.Errorf("register component type %s failed, %w", factory.Type(), err)
+			for _, registered := range factories[:i] {
+				registry.Unregister(registered.Type())
+			}
+			return
+		}
+	}
+	return
 }
 
-func MustRegister(registry IFactoryRegistry, component IComponentFactory) {
-	err := registry.Register(component)
+func MustRegister(registry IFactoryRegistry, factories ...IComponentFactory) {
+	err := Register(registry, factories...)
 	if err != nil {
 		panic(err)
 	}
@@ -2,17 +2,32 @@ package compcont
 
 import (
 	"fmt"
+	"path"
+	"slices"
 	"sync"
 )
 
 type FactoryRegistry struct {
 	factories map[ComponentTypeID]IComponentFactory
+	aliases   map[ComponentTypeID]TypeAlias
+	parent    IFactoryRegistry // 本地未注册的类型从父注册器中查找
 	mu        sync.RWMutex
 }
 
 func NewFactoryRegistry() IFactoryRegistry {
 	return &FactoryRegistry{
 		factories: make(map[ComponentTypeID]IComponentFactory),
+		aliases:   make(map[ComponentTypeID]TypeAlias),
+	}
+}
+
+// NewChildFactoryRegistry 创建叠加在parent之上的注册器，注册的工厂只在本地可见，本地未注册的类型从parent中查找，
+// 本地注册的工厂会遮蔽parent中同类型的工厂
+func NewChildFactoryRegistry(parent IFactoryRegistry) IFactoryRegistry {
+	return &FactoryRegistry{
+		factories: make(map[ComponentTypeID]IComponentFactory),
+		aliases:   make(map[ComponentTypeID]TypeAlias),
+		parent:    parent,
 	}
 }
 
@@ -23,6 +38,9 @@ func (c *FactoryRegistry) Register(f IComponentFactory) error {
 	if _, ok := c.factories[f.Type()]; ok {
 		return ErrComponentTypeAlreadyRegistered
 	}
+	if _, ok := c.aliases[f.Type()]; ok {
+		return fmt.Errorf("%w, %s is registered as an alias", ErrComponentTypeAlreadyRegistered, f.Type())
+	}
 	c.factories[f.Type()] = f
 	return nil
 }
@@ -31,6 +49,10 @@ func (c *FactoryRegistry) Register(f IComponentFactory) error {
 func (c *FactoryRegistry) Unregister(t ComponentTypeID) error {
 	c.mu.Lock()
 	defer c.mu.Unlock()
+	if _, ok := c.aliases[t]; ok {
+		delete(c.aliases, t)
+		return nil
+	}
 	if _, ok := c.factories[t]; !ok {
 		return ErrComponentTypeNotRegistered
 	}
@@ -45,19 +67,129 @@ func (c *FactoryRegistry) RegisteredComponentTypes() (types []ComponentTypeID) {
 	for t := range c.factories {
 		types = append(types, t)
 	}
+	if c.parent != nil {
+		for _, t := range c.parent.RegisteredComponentTypes() {
+			if _, ok := c.factories[t]; !ok {
+				types = append(types, t)
+			}
+		}
+	}
 	return
 }
 
 func (c *FactoryRegistry) GetFactory(t ComponentTypeID) (f IComponentFactory, err error) {
 	c.mu.RLock()
-	defer c.mu.RUnlock()
-	var ok bool
-	f, ok = c.factories[t]
-	if !ok {
-		err = fmt.Errorf("%w, component type: %s", ErrComponentTypeNotRegistered, t)
+	f, ok := c.factories[t]
+	alias, isAlias := c.aliases[t]
+	c.mu.RUnlock()
+	switch {
+	case ok:
 		return
+	case isAlias:
+		// 目标类型可能由本地或父注册器提供
+		return c.GetFactory(alias.Target)
+	case c.parent != nil:
+		return c.parent.GetFactory(t)
+	}
+	err = fmt.Errorf("%w, component type: %s", ErrComponentTypeNotRegistered, t)
+	return
+}
+
+// FactoryFilter 限制注册器可以提供的组件类型，类型可以使用 path.Match 的通配符，如 contrib.*
+type FactoryFilter struct {
+	Allow []ComponentTypeID // 不为空时只提供匹配的类型
+	Deny  []ComponentTypeID // 不提供匹配的类型，优先于Allow
+}
+
+func (f FactoryFilter) allowed(t ComponentTypeID) bool {
+	match := func(patterns []ComponentTypeID) bool {
+		return slices.ContainsFunc(patterns, func(pattern ComponentTypeID) bool {
+			ok, _ := path.Match(string(pattern), string(t))
+			return ok
+		})
+	}
+	if match(f.Deny) {
+		return false
+	}
+	return len(f.Allow) == 0 || match(f.Allow)
+}
+
+type filteredFactoryRegistry struct {
+	IFactoryRegistry
+	filter FactoryFilter
+}
+
+// NewFilteredFactoryRegistry 按filter过滤inner提供的组件类型，获取被过滤的类型时返回 ErrComponentTypeNotAllowed，注册操作直接作用于inner
+func NewFilteredFactoryRegistry(inner IFactoryRegistry, filter FactoryFilter) IFactoryRegistry {
+	return &filteredFactoryRegistry{IFactoryRegistry: inner, filter: filter}
+}
+
+func (r *filteredFactoryRegistry) RegisteredComponentTypes() (types []ComponentTypeID) {
+	for _, t := range r.IFactoryRegistry.RegisteredComponentTypes() {
+		if r.filter.allowed(t) {
+			types = append(types, t)
+		}
 	}
 	return
 }
 
+func (r *filteredFactoryRegistry) GetFactory(t ComponentTypeID) (f IComponentFactory, err error) {
+	if !r.filter.allowed(t) {
+		err = fmt.Errorf("%w, component type: %s", ErrComponentTypeNotAllowed, t)
+		return
+	}
+	f, err = r.IFactoryRegistry.GetFactory(t)
+	// 别名指向的类型同样需要被允许
+	if err == nil && !r.filter.allowed(f.Type()) {
+		err = fmt.Errorf("%w, component type: %s, alias of %s", ErrComponentTypeNotAllowed, f.Type(), t)
+		f = nil
+	}
+	return
+}
+
+// RegisterAlias implements IAliasRegistry.
+func (r *filteredFactoryRegistry) RegisterAlias(alias TypeAlias) error {
+	return RegisterAlias(r.IFactoryRegistry, alias)
+}
+
+// GetAlias implements IAliasRegistry.
+func (r *filteredFactoryRegistry) GetAlias(t ComponentTypeID) (alias TypeAlias, ok bool) {
+	if !r.filter.allowed(t) {
+		return
+	}
+	// 与 GetFactory 一致，别名指向的类型同样需要被允许
+	if alias, ok = GetAlias(r.IFactoryRegistry, t); ok && !r.filter.allowed(alias.Target) {
+		alias, ok = TypeAlias{}, false
+	}
+	return
+}
+
+// RegisteredAliases implements IAliasRegistry.
+func (r *filteredFactoryRegistry) RegisteredAliases() (aliases []TypeAlias) {
+	for _, alias := range RegisteredAliases(r.IFactoryRegistry) {
+		if r.filter.allowed(alias.Alias) && r.filter.allowed(alias.Target) {
+			aliases = append(aliases, alias)
+		}
+	}
+	return
+}
+
+// CloneFactoryRegistry 复制registry当前提供的全部工厂到一个新的注册器，之后两者的注册互不影响，
+// 例如测试中复制 DefaultFactoryRegistry 后再注册测试用的工厂
+func CloneFactoryRegistry(registry IFactoryRegistry) IFactoryRegistry {
+	clone := &FactoryRegistry{
+		factories: make(map[ComponentTypeID]IComponentFactory),
+		aliases:   make(map[ComponentTypeID]TypeAlias),
+	}
+	for _, t := range registry.RegisteredComponentTypes() {
+		if f, err := registry.GetFactory(t); err == nil {
+			clone.factories[t] = f
+		}
+	}
+	for _, alias := range RegisteredAliases(registry) {
+		clone.aliases[alias.Alias] = alias
+	}
+	return clone
+}
+
 var DefaultFactoryRegistry IFactoryRegistry = NewFactoryRegistry()
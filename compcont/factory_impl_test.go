@@ -0,0 +1,100 @@
+package compcont
+
+import (
+	"bytes"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChildFactoryRegistry(t *testing.T) {
+	newFactory := func(typeID ComponentTypeID) IComponentFactory {
+		return &TypedSimpleComponentFactory[string, string]{
+			TypeID: typeID,
+			CreateInstanceFunc: func(ctx Context, config string) (instance string, err error) {
+				return string(typeID) + ":" + config, nil
+			},
+		}
+	}
+	parent := NewFactoryRegistry()
+	MustRegister(parent, newFactory("std.echo"))
+	MustRegister(parent, newFactory("contrib.echo"))
+
+	child := NewChildFactoryRegistry(parent)
+	MustRegister(child, newFactory("local.echo"))
+	assert.ElementsMatch(t, []ComponentTypeID{"std.echo", "contrib.echo", "local.echo"}, child.RegisteredComponentTypes())
+	_, err := parent.GetFactory("local.echo")
+	assert.ErrorIs(t, err, ErrComponentTypeNotRegistered)
+
+	filtered := NewFilteredFactoryRegistry(child, FactoryFilter{Allow: []ComponentTypeID{"std.*", "local.*"}, Deny: []ComponentTypeID{"local.echo"}})
+	assert.ElementsMatch(t, []ComponentTypeID{"std.echo"}, filtered.RegisteredComponentTypes())
+
+	cc := NewComponentContainer(WithFactoryRegistry(filtered))
+	assert.NoError(t, cc.LoadNamedComponents([]ComponentConfig{{Name: "a", Type: "std.echo", Config: "a"}}))
+	err = cc.LoadNamedComponents([]ComponentConfig{{Name: "b", Type: "contrib.echo", Config: "b"}})
+	assert.ErrorIs(t, err, ErrComponentTypeNotAllowed)
+	err = cc.LoadNamedComponents([]ComponentConfig{{Name: "c", Type: "local.echo", Config: "c"}})
+	assert.ErrorIs(t, err, ErrComponentTypeNotAllowed)
+}
+
+func TestRegisterAndClone(t *testing.T) {
+	newFactory := func(typeID ComponentTypeID) IComponentFactory {
+		return &TypedSimpleComponentFactory[string, string]{TypeID: typeID}
+	}
+	r := NewFactoryRegistry()
+	MustRegister(r, newFactory("a"))
+
+	// 注册失败时撤销本次已注册的工厂
+	err := Register(r, newFactory("b"), newFactory("a"))
+	assert.ErrorIs(t, err, ErrComponentTypeAlreadyRegistered)
+	assert.ElementsMatch(t, []ComponentTypeID{"a"}, r.RegisteredComponentTypes())
+
+	clone := CloneFactoryRegistry(r)
+	MustRegister(clone, newFactory("b"))
+	assert.ElementsMatch(t, []ComponentTypeID{"a", "b"}, clone.RegisteredComponentTypes())
+	assert.ElementsMatch(t, []ComponentTypeID{"a"}, r.RegisteredComponentTypes())
+}
+
+func TestTypeAlias(t *testing.T) {
+	r := NewFactoryRegistry()
+	MustRegister(r, &TypedSimpleComponentFactory[string, string]{
+		TypeID:  "contrib.echo",
+		Version: "2",
+		CreateInstanceFunc: func(ctx Context, config string) (instance string, err error) {
+			return config, nil
+		},
+	})
+	assert.NoError(t, RegisterAlias(r, TypeAlias{Alias: "base.echo", Target: "contrib.echo", Deprecated: true, Since: "v1"}))
+	assert.ErrorIs(t, RegisterAlias(r, TypeAlias{Alias: "base.echo", Target: "contrib.echo"}), ErrComponentTypeAlreadyRegistered)
+	assert.ErrorIs(t, RegisterAlias(r, TypeAlias{Alias: "old.echo", Target: "base.echo"}), ErrComponentTypeAliasInvalid)
+	// 已被其他别名指向的名称不能再注册为别名
+	assert.NoError(t, RegisterAlias(r, TypeAlias{Alias: "legacy.echo", Target: "next.echo"}))
+	assert.ErrorIs(t, RegisterAlias(r, TypeAlias{Alias: "next.echo", Target: "contrib.echo"}), ErrComponentTypeAliasInvalid)
+	assert.NoError(t, r.Unregister("legacy.echo"))
+
+	// 未实现 IAliasRegistry 的注册器不支持别名
+	assert.ErrorIs(t, RegisterAlias(struct{ IFactoryRegistry }{r}, TypeAlias{Alias: "x.echo", Target: "contrib.echo"}), ErrComponentTypeAliasInvalid)
+
+	var logs bytes.Buffer
+	cc := NewComponentContainer(WithFactoryRegistry(NewChildFactoryRegistry(r)), WithLogger(slog.New(slog.NewTextHandler(&logs, nil))))
+	assert.NoError(t, cc.LoadNamedComponents([]ComponentConfig{{Name: "a", Type: "base.echo", Config: "a"}}))
+	a, err := GetComponent[string](cc, "a")
+	assert.NoError(t, err)
+	assert.Equal(t, "a", a.Instance)
+	assert.Contains(t, logs.String(), "replacement=contrib.echo")
+	assert.Contains(t, logs.String(), "replacement_version=2")
+
+	assert.Equal(t, []TypeAlias{{Alias: "base.echo", Target: "contrib.echo", Deprecated: true, Since: "v1"}}, RegisteredAliases(CloneFactoryRegistry(r)))
+
+	// 别名指向的类型被过滤时同样不能使用
+	filtered := NewFilteredFactoryRegistry(r, FactoryFilter{Allow: []ComponentTypeID{"base.*"}})
+	_, err = filtered.GetFactory("base.echo")
+	assert.ErrorIs(t, err, ErrComponentTypeNotAllowed)
+	_, ok := GetAlias(filtered, "base.echo")
+	assert.False(t, ok)
+	assert.Empty(t, RegisteredAliases(filtered))
+	alias, ok := GetAlias(NewFilteredFactoryRegistry(r, FactoryFilter{Allow: []ComponentTypeID{"base.*", "contrib.*"}}), "base.echo")
+	assert.True(t, ok)
+	assert.Equal(t, ComponentTypeID("contrib.echo"), alias.Target)
+}
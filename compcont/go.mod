@@ -3,6 +3,7 @@ module github.com/go-compcont/compcont/compcont
 go 1.23.1
 
 require (
+	github.com/expr-lang/expr v1.16.9
 	github.com/mitchellh/mapstructure v1.5.0
 	github.com/stretchr/testify v1.9.0
 )
@@ -0,0 +1,99 @@
+package compcont
+
+import (
+	"fmt"
+	"strings"
+)
+
+// 依赖图中的一个具名组件
+type GraphNode struct {
+	Path    string           `json:"path"`
+	TypeID  ComponentTypeID  `json:"type,omitempty"`
+	State   ComponentState   `json:"state"`
+	Modules []ModuleSnapshot `json:"modules,omitempty"` // 组件是子容器时，加载到其中的模块
+}
+
+// 依赖图中的一条边，From依赖To
+type GraphEdge struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// DependencyGraph 容器及其子容器中具名组件之间的依赖图，节点以组件的绝对路径标识
+type DependencyGraph struct {
+	Modules []ModuleSnapshot `json:"modules,omitempty"` // 加载到根容器中的模块
+	Nodes   []GraphNode      `json:"nodes"`
+	Edges   []GraphEdge      `json:"edges"`
+}
+
+// Graph 根据快照中记录的实际依赖生成依赖图，子容器中的组件一并展开
+func (s ContainerSnapshot) Graph() (graph DependencyGraph) {
+	graph = DependencyGraph{Modules: s.Modules, Nodes: []GraphNode{}, Edges: []GraphEdge{}}
+	var walk func(snapshot ContainerSnapshot)
+	walk = func(snapshot ContainerSnapshot) {
+		for _, component := range snapshot.Components {
+			path := formatPath(component.Path)
+			node := GraphNode{Path: path, TypeID: component.TypeID, State: component.State}
+			if component.Container != nil {
+				node.Modules = component.Container.Modules
+			}
+			graph.Nodes = append(graph.Nodes, node)
+			for _, dep := range component.Dependencies {
+				graph.Edges = append(graph.Edges, GraphEdge{From: path, To: formatPath(dep)})
+			}
+			if component.Container != nil {
+				walk(*component.Container)
+			}
+		}
+	}
+	walk(s)
+	return
+}
+
+var dotEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+
+// DOT 以graphviz的DOT格式输出依赖图
+func (g DependencyGraph) DOT() string {
+	var b strings.Builder
+	b.WriteString("digraph compcont {\n")
+	b.WriteString("  rankdir=LR;\n")
+	if len(g.Modules) > 0 {
+		fmt.Fprintf(&b, "  label=\"%s\";\n", moduleLabel(g.Modules))
+	}
+	b.WriteString("  node [shape=box];\n")
+	for _, node := range g.Nodes {
+		// 类型和模块另起一行显示，\n由graphviz解释为换行
+		label := dotEscaper.Replace(node.Path)
+		if node.TypeID != "" {
+			label += `\n` + dotEscaper.Replace(string(node.TypeID))
+		}
+		if len(node.Modules) > 0 {
+			label += `\n` + moduleLabel(node.Modules)
+		}
+		attrs := `label="` + label + `"`
+		if node.State == StateFailed {
+			attrs += ", color=red"
+		} else if node.State == StateDeferred {
+			attrs += ", style=dashed"
+		}
+		fmt.Fprintf(&b, "  \"%s\" [%s];\n", dotEscaper.Replace(node.Path), attrs)
+	}
+	for _, edge := range g.Edges {
+		fmt.Fprintf(&b, "  \"%s\" -> \"%s\";\n", dotEscaper.Replace(edge.From), dotEscaper.Replace(edge.To))
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// 模块的DOT标签，如 module http (imports base, zap)
+func moduleLabel(modules []ModuleSnapshot) string {
+	var parts []string
+	for _, module := range modules {
+		part := "module " + module.Name
+		if len(module.Imports) > 0 {
+			part += " (imports " + strings.Join(module.Imports, ", ") + ")"
+		}
+		parts = append(parts, dotEscaper.Replace(part))
+	}
+	return strings.Join(parts, `\n`)
+}
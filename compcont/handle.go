@@ -0,0 +1,156 @@
+package compcont
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// 默认的旧实例宽限期，超过该时间后即使仍有使用者也会销毁旧实例
+const DefaultHandleGracePeriod = 30 * time.Second
+
+// Handle 一个始终指向组件当前实例的句柄，组件被热重载时原子地切换到新实例
+//
+// 通过 Acquire 获取的实例在release之前不会被销毁，旧实例会在所有使用者release后或宽限期结束后销毁
+type Handle[Instance any] struct {
+	current atomic.Pointer[handleSlot[Instance]]
+}
+
+type handleSlot[Instance any] struct {
+	component TypedComponent[Instance]
+	removed   bool // 组件已被卸载或删除，此时component中只保留上下文
+	inflight  atomic.Int64
+	retired   atomic.Bool
+	drained   chan struct{}
+	once      sync.Once
+}
+
+func newHandleSlot[Instance any](component TypedComponent[Instance]) *handleSlot[Instance] {
+	return &handleSlot[Instance]{component: component, drained: make(chan struct{})}
+}
+
+func (s *handleSlot[Instance]) release() {
+	if s.inflight.Add(-1) == 0 && s.retired.Load() {
+		s.once.Do(func() { close(s.drained) })
+	}
+}
+
+// 标记实例已被替换，返回的channel在所有使用者release后关闭
+func (s *handleSlot[Instance]) retire() <-chan struct{} {
+	s.retired.Store(true)
+	if s.inflight.Load() == 0 {
+		s.once.Do(func() { close(s.drained) })
+	}
+	return s.drained
+}
+
+// Get 获取当前实例，不跟踪使用状态，适合调用期间实例被替换也无妨的场景，组件被删除后返回零值
+func (h *Handle[Instance]) Get() Instance {
+	return h.current.Load().component.Instance
+}
+
+// Component 获取当前组件
+func (h *Handle[Instance]) Component() TypedComponent[Instance] {
+	return h.current.Load().component
+}
+
+// Err 组件被卸载或在热重载中被删除后返回错误
+func (h *Handle[Instance]) Err() error {
+	if slot := h.current.Load(); slot.removed {
+		return fmt.Errorf("%w, component %s of the handle has been removed", ErrComponentNameNotFound, slot.component.Context.Config.Name)
+	}
+	return nil
+}
+
+// Acquire 获取当前实例并标记为使用中，使用完毕后必须调用release，在此之前实例不会因热重载而被销毁，组件被删除后返回零值
+func (h *Handle[Instance]) Acquire() (instance Instance, release func()) {
+	for {
+		slot := h.current.Load()
+		slot.inflight.Add(1)
+		if h.current.Load() == slot {
+			return slot.component.Instance, sync.OnceFunc(slot.release)
+		}
+		// 获取期间实例已被替换，重试
+		slot.release()
+	}
+}
+
+// accepts implements componentHandle.
+func (h *Handle[Instance]) accepts(component Component) bool {
+	_, ok := component.Instance.(Instance)
+	return ok
+}
+
+// replace implements componentHandle.
+func (h *Handle[Instance]) replace(component Component) (drained <-chan struct{}) {
+	slot := newHandleSlot(TypedComponent[Instance]{
+		Context:  component.Context,
+		Instance: component.Instance.(Instance),
+	})
+	return h.current.Swap(slot).retire()
+}
+
+// remove implements componentHandle.
+func (h *Handle[Instance]) remove() (drained <-chan struct{}) {
+	slot := newHandleSlot(TypedComponent[Instance]{Context: h.current.Load().component.Context})
+	slot.removed = true
+	return h.current.Swap(slot).retire()
+}
+
+// 容器持有的句柄，与具体实例类型无关，调用方需持有容器的锁
+type componentHandle interface {
+	accepts(component Component) bool
+	replace(component Component) (drained <-chan struct{}) // 切换到新实例，返回旧实例的使用者全部release后关闭的channel
+	remove() (drained <-chan struct{})                     // 组件被删除时不再指向任何实例
+}
+
+// GetHandle 获取具名组件的句柄，同一容器中同名同类型的句柄只会创建一个，container可以是包装了容器的 IContainerWrapper
+func GetHandle[Instance any](container IComponentContainer, name ComponentName) (h *Handle[Instance], err error) {
+	c, ok := unwrapContainer[*ComponentContainer](container)
+	if !ok {
+		err = fmt.Errorf("container %T does not support handles", container)
+		return
+	}
+	if !c.isSingleton(name) {
+		err = fmt.Errorf("%w, only singleton components have handles, name: %s", ErrComponentConfigInvalid, name)
+		return
+	}
+	// 懒加载组件需要先实例化
+	if _, err = c.GetComponent(name); err != nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	component, ok := c.components[name]
+	if !ok {
+		err = fmt.Errorf("%w, name: %s", ErrComponentNameNotFound, name)
+		return
+	}
+	typ := reflect.TypeFor[Instance]()
+	if existing, ok := c.handles[name][typ]; ok {
+		h = existing.(*Handle[Instance])
+		return
+	}
+	instance, ok := component.Instance.(Instance)
+	if !ok {
+		err = fmt.Errorf("get handle failed, %w, name: %s, component type: %s, expected instance type %v, but got %v", ErrComponentTypeMismatch, name, component.Context.Config.Type, typ, reflect.TypeOf(component.Instance))
+		return
+	}
+	h = &Handle[Instance]{}
+	h.current.Store(newHandleSlot(TypedComponent[Instance]{Context: component.Context, Instance: instance}))
+	if _, ok := c.handles[name]; !ok {
+		c.handles[name] = make(map[reflect.Type]componentHandle)
+	}
+	c.handles[name][typ] = h
+	return
+}
+
+// WithHandleGracePeriod 指定热重载后等待旧实例使用者release的最长时间，默认为 DefaultHandleGracePeriod
+func WithHandleGracePeriod(d time.Duration) optionsFunc {
+	return func(o *options) {
+		o.handleGracePeriod = d
+	}
+}
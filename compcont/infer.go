@@ -0,0 +1,252 @@
+package compcont
+
+import (
+	"reflect"
+	"strings"
+	"time"
+)
+
+// 可以提供引用路径的配置值，如 Ref
+type referPather interface {
+	referPath() string
+}
+
+// 可以转换为 ComponentConfig 的配置值，如 TypedComponentConfig
+type componentConfigConverter interface {
+	ToAny() ComponentConfig
+}
+
+// 收集容器中组件的配置值，如 Collection
+type componentCollector interface {
+	collects() (selector Selector, exclude []ComponentName, enabled bool)
+}
+
+var referPatherType = reflect.TypeFor[referPather]()
+
+// 从组件配置中推断其依赖的同容器组件
+//
+// 会扫描组件自身的refer、配置中嵌套的 {refer: ...}、Ref 以及嵌套组件配置中的deps，工厂实现了 IComponentConfigDecoder 时先解码配置再扫描，
+// 对于子容器中声明的具名组件，其中的相对路径需要先通过 .. 回到当前容器才视为依赖
+func inferDeps(registry IFactoryRegistry, config ComponentConfig) (deps set[ComponentName]) {
+	return scanConfig(registry, config, nil).deps
+}
+
+// 扫描组件配置，得到推断出的依赖以及在当前容器中启用的 Collection，decoded不为空时复用其中已解码的配置
+func scanConfig(registry IFactoryRegistry, config ComponentConfig, decoded *decodedConfig) *depScanner {
+	if !decoded.reusable(config) {
+		decoded = decodeComponentConfig(registry, config)
+	}
+	s := &depScanner{registry: registry, deps: make(set[ComponentName]), visited: make(map[uintptr]struct{}), decoded: decoded}
+	s.scanComponentConfig(config, 0, decoded)
+	return s
+}
+
+// 由工厂解码后的组件配置，排序时解码一次，构造组件和记录推断的依赖时复用
+type decodedConfig struct {
+	typ      ComponentTypeID // 解码时使用的组件类型，与组件配置不一致时不能复用
+	config   any
+	err      error
+	duration time.Duration
+}
+
+// 使用工厂解码组件配置，工厂不存在或未实现 IComponentConfigDecoder 时返回nil
+func decodeComponentConfig(registry IFactoryRegistry, config ComponentConfig) *decodedConfig {
+	if config.Type == "" || registry == nil {
+		return nil
+	}
+	factory, err := registry.GetFactory(config.Type)
+	if err != nil {
+		return nil
+	}
+	decoder, ok := factory.(IComponentConfigDecoder)
+	if !ok {
+		return nil
+	}
+	start := time.Now()
+	decoded := &decodedConfig{typ: config.Type}
+	decoded.config, decoded.err = decoder.DecodeConfig(config.Config)
+	decoded.duration = time.Since(start)
+	return decoded
+}
+
+// 对同一类型复用已解码的配置
+func (d *decodedConfig) reusable(config ComponentConfig) bool {
+	return d != nil && d.typ == config.Type
+}
+
+type depScanner struct {
+	registry   IFactoryRegistry
+	deps       set[ComponentName]
+	collectors []componentCollector // 在当前容器中收集组件的 Collection，子容器中的不计入
+	visited    map[uintptr]struct{}
+	decoded    *decodedConfig // 被扫描的组件自身的解码结果，嵌套的组件配置不计入
+}
+
+// 添加一个依赖路径，depth表示该路径所在的组件相对于当前容器的嵌套层数
+func (s *depScanner) addPath(path string, depth int) {
+	if name, ok := localDependency(path, depth); ok {
+		s.deps[name] = struct{}{}
+	}
+}
+
+// 计算一个引用路径在当前容器中对应的组件名，depth表示该路径所在的组件相对于当前容器的嵌套层数，
+// 路径指向其他容器(祖先容器或绝对路径)时返回false
+func localDependency(path string, depth int) (name ComponentName, ok bool) {
+	parts, absolute, err := parseReferPath(path)
+	if err != nil || absolute || len(parts) == 0 {
+		return
+	}
+	// 沿祖先容器查找的引用可能落在当前容器中，子容器中的同名组件会遮蔽它，按依赖处理只会多一条加载顺序的约束，由调用方判断名称是否存在
+	if ancestor, found := strings.CutPrefix(string(parts[0]), AncestorReferPrefix); found {
+		return ComponentName(ancestor), true
+	}
+	for len(parts) > 0 && parts[0] == "." {
+		parts = parts[1:]
+	}
+	for depth > 0 && len(parts) > 0 && parts[0] == ".." {
+		depth--
+		parts = parts[1:]
+	}
+	if depth != 0 || len(parts) == 0 || parts[0] == ".." || parts[0] == "." {
+		return
+	}
+	return parts[0], true
+}
+
+// decoded为组件配置的解码结果，为空时按需解码
+func (s *depScanner) scanComponentConfig(config ComponentConfig, depth int, decoded *decodedConfig) {
+	if config.Type == "" && config.Refer != "" {
+		s.addPath(config.Refer, depth)
+	}
+	if config.Template != "" {
+		s.addPath(config.Template, depth)
+	}
+	// 子容器中具名组件的deps，只有通过 .. 指向当前容器的才需要关心
+	if depth > 0 {
+		for _, dep := range config.Deps {
+			s.addPath(string(dep), depth)
+		}
+	}
+	// 尽可能将配置解码为工厂的具体配置类型，以便找到以纯字符串形式出现的 Ref
+	if decoded == nil {
+		decoded = decodeComponentConfig(s.registry, config)
+	}
+	value := config.Config
+	// 配置错误留到组件构造时再报告
+	if decoded != nil && decoded.err == nil {
+		value = decoded.config
+	}
+	s.scan(reflect.ValueOf(value), depth)
+}
+
+func (s *depScanner) scan(v reflect.Value, depth int) {
+	if !v.IsValid() {
+		return
+	}
+
+	if v.Type().Implements(referPatherType) {
+		if (v.Kind() != reflect.Pointer && v.Kind() != reflect.Interface) || !v.IsNil() {
+			s.addPath(v.Interface().(referPather).referPath(), depth)
+		}
+		return
+	}
+	if v.CanInterface() {
+		if collector, ok := v.Interface().(componentCollector); ok {
+			if v.Kind() != reflect.Pointer || !v.IsNil() {
+				if _, _, enabled := collector.collects(); enabled && depth == 0 {
+					s.collectors = append(s.collectors, collector)
+				}
+			}
+			return
+		}
+		if converter, ok := v.Interface().(componentConfigConverter); ok {
+			if v.Kind() != reflect.Pointer || !v.IsNil() {
+				cfg := converter.ToAny()
+				s.scanComponentConfig(cfg, depth+nestedDepth(cfg), nil)
+			}
+			return
+		}
+	}
+
+	switch v.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() {
+			return
+		}
+		if _, ok := s.visited[v.Pointer()]; ok {
+			return
+		}
+		s.visited[v.Pointer()] = struct{}{}
+		s.scan(v.Elem(), depth)
+	case reflect.Interface:
+		s.scan(v.Elem(), depth)
+	case reflect.Struct:
+		if cfg, ok := v.Interface().(ComponentConfig); ok {
+			s.scanComponentConfig(cfg, depth+nestedDepth(cfg), nil)
+			return
+		}
+		for i := range v.NumField() {
+			if v.Type().Field(i).IsExported() {
+				s.scan(v.Field(i), depth)
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		for i := range v.Len() {
+			s.scan(v.Index(i), depth)
+		}
+	case reflect.Map:
+		if m, ok := v.Interface().(map[string]any); ok {
+			s.scanMap(m, depth)
+			return
+		}
+		iter := v.MapRange()
+		for iter.Next() {
+			s.scan(iter.Value(), depth)
+		}
+	}
+}
+
+// 扫描未解码的map形式配置，形如组件配置的map按组件配置处理
+func (s *depScanner) scanMap(m map[string]any, depth int) {
+	refer, hasRefer := m["refer"].(string)
+	typ, _ := m["type"].(string)
+	if !hasRefer && typ == "" {
+		for _, value := range m {
+			s.scan(reflect.ValueOf(value), depth)
+		}
+		return
+	}
+
+	name, _ := m["name"].(string)
+	cfg := ComponentConfig{
+		Name:   ComponentName(name),
+		Type:   ComponentTypeID(typ),
+		Refer:  refer,
+		Config: m["config"],
+	}
+	if deps, ok := m["deps"].([]any); ok {
+		for _, dep := range deps {
+			if dep, ok := dep.(string); ok {
+				cfg.Deps = append(cfg.Deps, ComponentName(dep))
+			}
+		}
+	}
+	s.scanComponentConfig(cfg, depth+nestedDepth(cfg), nil)
+
+	// 可能并非组件配置而只是恰好含有type字段，其余字段也需要扫描
+	for key, value := range m {
+		switch key {
+		case "name", "type", "refer", "deps", "config":
+		default:
+			s.scan(reflect.ValueOf(value), depth)
+		}
+	}
+}
+
+// 具名组件只可能声明在子容器中，其引用路径相对于子容器，需要多嵌套一层
+func nestedDepth(config ComponentConfig) int {
+	if config.Name != "" {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,274 @@
+package compcont
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+)
+
+type ComponentState string
+
+const (
+	StateCreating   ComponentState = "creating"   // 正在创建
+	StateReady      ComponentState = "ready"      // 已创建，可以使用
+	StateDeferred   ComponentState = "deferred"   // 懒加载、scoped或transient组件，实例在获取时创建
+	StateFailed     ComponentState = "failed"     // 创建失败，组件不在容器中，重新加载成功、卸载或热重载后不再保留
+	StateDestroying ComponentState = "destroying" // 正在销毁
+)
+
+// 具名组件的生命周期状态
+type componentStatus struct {
+	state    ComponentState
+	config   ComponentConfig // 展开模板后实际用于加载的配置
+	loadedAt time.Time
+	duration time.Duration
+	lastErr  error
+}
+
+// 快照中需要隐藏的配置字段，字段名包含其中任意一项时(不区分大小写)值被替换为 RedactedValue，
+// URL形式的字符串值中的用户信息及查询参数中的这些字段同样会被隐藏
+var RedactedConfigKeys = []string{"password", "passwd", "secret", "token", "credential", "private_key", "access_key", "dsn"}
+
+const RedactedValue = "******"
+
+// ComponentSnapshot 一个具名组件在快照时刻的只读信息
+type ComponentSnapshot struct {
+	Path         []ComponentName    `json:"path"`
+	TypeID       ComponentTypeID    `json:"type,omitempty"`
+	Refer        string             `json:"refer,omitempty"`
+	ReferTarget  []ComponentName    `json:"refer_target,omitempty"` // refer解析得到的组件的绝对路径
+	Template     string             `json:"template,omitempty"`
+	DeclaredDeps []ComponentName    `json:"declared_deps,omitempty"`
+	InferredDeps []ComponentName    `json:"inferred_deps,omitempty"` // 从配置中推断出的同容器依赖
+	Dependencies [][]ComponentName  `json:"dependencies,omitempty"`  // 加载时实际依赖的组件的绝对路径，可跨容器
+	Config       any                `json:"config,omitempty"`        // 隐藏敏感字段后的实际配置
+	Params       any                `json:"params,omitempty"`        // 隐藏敏感字段后的模板参数
+	Scope        ComponentScope     `json:"scope,omitempty"`
+	Lazy         bool               `json:"lazy,omitempty"`
+	Labels       map[string]string  `json:"labels,omitempty"`
+	Annotations  map[string]string  `json:"annotations,omitempty"`
+	InstanceType string             `json:"instance_type,omitempty"` // 实例的Go类型，未实例化时为空
+	State        ComponentState     `json:"state"`
+	LoadedAt     time.Time          `json:"loaded_at,omitempty"`
+	LoadDuration time.Duration      `json:"load_duration,omitempty"`
+	LastError    string             `json:"last_error,omitempty"`
+	Container    *ContainerSnapshot `json:"container,omitempty"` // 组件是以当前容器为父容器的子容器时，子容器的快照
+}
+
+// ContainerSnapshot 容器在快照时刻的只读信息，组件按名称排序
+type ContainerSnapshot struct {
+	Path       []ComponentName     `json:"path"`
+	Profile    string              `json:"profile,omitempty"`
+	Disabled   []ComponentName     `json:"disabled,omitempty"`
+	Exports    []ComponentName     `json:"exports,omitempty"` // 对外可见的组件，为空表示全部可见
+	Modules    []ModuleSnapshot    `json:"modules,omitempty"` // 通过 LoadModule 加载到容器中的模块
+	Components []ComponentSnapshot `json:"components"`
+}
+
+// 清除满足match的创建失败且不在容器中的组件的状态，失败已被处理后不再报告
+func (c *ComponentContainer) clearFailed(match func(name ComponentName) bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for name, status := range c.statuses {
+		if _, loaded := c.components[name]; !loaded && status.state == StateFailed && match(name) {
+			delete(c.statuses, name)
+		}
+	}
+}
+
+// 创建失败且不在容器中的组件
+func (c *ComponentContainer) isFailed(name ComponentName) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	status, ok := c.statuses[name]
+	_, loaded := c.components[name]
+	return ok && !loaded && status.state == StateFailed
+}
+
+func (c *ComponentContainer) setStatus(name ComponentName, update func(status *componentStatus)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	status, ok := c.statuses[name]
+	if !ok {
+		status = &componentStatus{}
+		c.statuses[name] = status
+	}
+	update(status)
+}
+
+// Snapshot implements ISnapshotContainer，不会触发懒加载组件的实例化
+func (c *ComponentContainer) Snapshot() (snapshot ContainerSnapshot) {
+	c.mu.RLock()
+	names := make(set[ComponentName])
+	for name := range c.components {
+		names[name] = struct{}{}
+	}
+	for name := range c.statuses {
+		names[name] = struct{}{}
+	}
+	components := maps.Clone(c.components)
+	configs := maps.Clone(c.configs)
+	statuses := make(map[ComponentName]componentStatus, len(c.statuses))
+	for name, status := range c.statuses {
+		statuses[name] = *status
+	}
+	dependencies := make(map[ComponentName][]componentKey, len(c.dependencies))
+	for name, keys := range c.dependencies {
+		for key := range keys {
+			dependencies[name] = append(dependencies[name], key)
+		}
+	}
+	snapshot = ContainerSnapshot{
+		Path:     containerPath(c),
+		Profile:  c.profile,
+		Disabled: sortedNames(c.disabled),
+		Exports:  sortedNames(c.exports),
+		Modules:  slices.Clone(c.modules),
+	}
+	c.mu.RUnlock()
+
+	for _, name := range sortedNames(names) {
+		component, loaded := components[name]
+		status := statuses[name]
+		if status.state == "" {
+			status.state = StateReady
+		}
+		config := status.config
+		if config.Name == "" {
+			config = component.Context.Config
+		}
+		s := ComponentSnapshot{
+			Path:         c.componentPath(name),
+			TypeID:       config.Type,
+			Refer:        config.Refer,
+			DeclaredDeps: config.Deps,
+			Config:       redactValue(config.Config),
+			Scope:        config.Scope,
+			Lazy:         config.Lazy,
+			Labels:       config.Labels,
+			Annotations:  config.Annotations,
+			State:        status.state,
+			LoadedAt:     status.loadedAt,
+			LoadDuration: status.duration,
+		}
+		if raw, ok := configs[name]; ok && raw.Template != "" {
+			s.Template = raw.Template
+			s.Params = redactValue(raw.Params)
+		}
+		if status.lastErr != nil {
+			s.LastError = status.lastErr.Error()
+		}
+		for _, key := range dependencies[name] {
+			s.Dependencies = append(s.Dependencies, append(containerPath(key.container), key.name))
+		}
+		slices.SortFunc(s.Dependencies, func(a, b []ComponentName) int {
+			return strings.Compare(formatPath(a), formatPath(b))
+		})
+		if config.Type != "" {
+			s.InferredDeps = sortedNames(inferDeps(c.factoryRegistry, config))
+		}
+		if loaded {
+			if isLazyPlaceholder(component) || isScopedPlaceholder(component) {
+				s.State = StateDeferred
+			} else {
+				s.InstanceType = fmt.Sprintf("%T", component.Instance)
+			}
+			if config.Refer != "" {
+				s.ReferTarget = component.Context.GetAbsolutePath()
+			}
+			if child, ok := component.Instance.(IComponentContainer); ok && child.GetParent() == IComponentContainer(c) {
+				if snapshotter, ok := unwrapContainer[ISnapshotContainer](child); ok {
+					childSnapshot := snapshotter.Snapshot()
+					s.Container = &childSnapshot
+				}
+			}
+		}
+		snapshot.Components = append(snapshot.Components, s)
+	}
+	return
+}
+
+func isRedactedKey(key string) bool {
+	key = strings.ToLower(key)
+	return slices.ContainsFunc(RedactedConfigKeys, func(redacted string) bool {
+		return strings.Contains(key, redacted)
+	})
+}
+
+// 隐藏URL形式的字符串中的凭据，如 redis://:pass@host 中的密码和查询参数中的敏感字段，其余字符串原样返回
+func redactURL(value string) string {
+	scheme, rest, ok := strings.Cut(value, "://")
+	if !ok || scheme == "" || strings.ContainsAny(scheme, " /") {
+		return value
+	}
+	rest, fragment, hasFragment := strings.Cut(rest, "#")
+	rest, query, hasQuery := strings.Cut(rest, "?")
+	authority, path := rest, ""
+	if i := strings.Index(rest, "/"); i >= 0 {
+		authority, path = rest[:i], rest[i:]
+	}
+	if at := strings.LastIndex(authority, "@"); at >= 0 {
+		if user, _, ok := strings.Cut(authority[:at], ":"); ok {
+			authority = user + ":" + RedactedValue + authority[at:]
+		} else {
+			// 只有用户名时可能是令牌，如 https://token@host
+			authority = RedactedValue + authority[at:]
+		}
+	}
+	var b strings.Builder
+	b.WriteString(scheme + "://" + authority + path)
+	if hasQuery {
+		pairs := strings.Split(query, "&")
+		for i, pair := range pairs {
+			key, _, _ := strings.Cut(pair, "=")
+			if unescaped, err := url.QueryUnescape(key); err == nil && isRedactedKey(unescaped) {
+				pairs[i] = key + "=" + RedactedValue
+			}
+		}
+		b.WriteString("?" + strings.Join(pairs, "&"))
+	}
+	if hasFragment {
+		b.WriteString("#" + fragment)
+	}
+	return b.String()
+}
+
+// 复制配置并隐藏其中的敏感字段，结构体等类型的配置先转换为通用的map
+func redactValue(value any) any {
+	switch v := value.(type) {
+	case string:
+		return redactURL(v)
+	case nil, bool, int, int64, uint64, float64:
+		return v
+	case map[string]any:
+		m := make(map[string]any, len(v))
+		for k, item := range v {
+			if isRedactedKey(k) {
+				m[k] = RedactedValue
+			} else {
+				m[k] = redactValue(item)
+			}
+		}
+		return m
+	case []any:
+		list := make([]any, len(v))
+		for i, item := range v {
+			list[i] = redactValue(item)
+		}
+		return list
+	default:
+		bs, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprintf("<%T>", v)
+		}
+		var generic any
+		if err = json.Unmarshal(bs, &generic); err != nil {
+			return fmt.Sprintf("<%T>", v)
+		}
+		return redactValue(generic)
+	}
+}
@@ -0,0 +1,99 @@
+package compcont
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type snapshotConfig struct {
+	URL      string `ccf:"url"`
+	Password string `ccf:"password"`
+}
+
+func TestSnapshot(t *testing.T) {
+	r := NewFactoryRegistry()
+	MustRegister(r, &TypedSimpleComponentFactory[snapshotConfig, *snapshotConfig]{
+		TypeID: "db",
+		CreateInstanceFunc: func(ctx Context, config snapshotConfig) (instance *snapshotConfig, err error) {
+			if config.URL == "" {
+				err = errors.New("url is required")
+			}
+			return &config, err
+		},
+	}, &TypedSimpleComponentFactory[[]ComponentConfig, IComponentContainer]{
+		TypeID: "inline",
+		CreateInstanceFunc: func(ctx Context, config []ComponentConfig) (instance IComponentContainer, err error) {
+			instance = NewComponentContainer(WithParentContainer(ctx.Container), WithContext(ctx), WithFactoryRegistry(r))
+			err = instance.LoadNamedComponents(config)
+			return
+		},
+	})
+	cc := NewComponentContainer(WithFactoryRegistry(r))
+	assert.NoError(t, cc.LoadNamedComponents([]ComponentConfig{
+		{Name: "db", Type: "db", Config: map[string]any{"url": "db://", "password": "p@ss"}},
+		{Name: "lazy_db", Type: "db", Lazy: true, Config: map[string]any{"url": "db://lazy"}},
+		{Name: "child", Type: "inline", Config: []ComponentConfig{
+			{Name: "alias", Refer: "../db", Deps: []ComponentName{"../db"}},
+		}},
+	}))
+	assert.Error(t, cc.LoadNamedComponents([]ComponentConfig{{Name: "broken", Type: "db"}}))
+	assert.Equal(t, []ComponentName{"child", "db", "lazy_db"}, cc.LoadedComponentNames())
+
+	snapshot := cc.(ISnapshotContainer).Snapshot()
+	assert.Len(t, snapshot.Components, 4)
+	byName := make(map[ComponentName]ComponentSnapshot)
+	for _, s := range snapshot.Components {
+		byName[s.Path[len(s.Path)-1]] = s
+	}
+
+	assert.Equal(t, StateFailed, byName["broken"].State)
+	assert.Contains(t, byName["broken"].LastError, "url is required")
+
+	db := byName["db"]
+	assert.Equal(t, StateReady, db.State)
+	assert.Equal(t, "*compcont.snapshotConfig", db.InstanceType)
+	assert.Equal(t, map[string]any{"url": "db://", "password": RedactedValue}, db.Config)
+	assert.False(t, db.LoadedAt.IsZero())
+
+	// URL中的凭据被隐藏
+	assert.Equal(t, map[string]any{
+		"url":   "redis://:******@localhost:6379/0",
+		"addrs": []any{"https://******@example.com", "db://user:******@host/db?sslmode=disable&password=******"},
+		"host":  "localhost:6379",
+	}, redactValue(map[string]any{
+		"url":   "redis://:pass@localhost:6379/0",
+		"addrs": []any{"https://token@example.com", "db://user:pass@host/db?sslmode=disable&password=p"},
+		"host":  "localhost:6379",
+	}))
+
+	assert.Equal(t, StateDeferred, byName["lazy_db"].State)
+	assert.Empty(t, byName["lazy_db"].InstanceType)
+
+	child := byName["child"].Container
+	if assert.NotNil(t, child) {
+		alias := child.Components[0]
+		assert.Equal(t, []ComponentName{"child", "alias"}, alias.Path)
+		assert.Equal(t, []ComponentName{"db"}, alias.ReferTarget)
+		assert.Equal(t, []ComponentName{"../db"}, alias.DeclaredDeps)
+	}
+
+	// 卸载后不再出现在快照中，依赖db的child一并被卸载
+	assert.NoError(t, cc.UnloadNamedComponents([]ComponentName{"db"}, true))
+	assert.Len(t, cc.(ISnapshotContainer).Snapshot().Components, 2)
+
+	// 卸载创建失败的组件后不再报告失败
+	assert.NoError(t, cc.UnloadNamedComponents([]ComponentName{"broken"}, false))
+	assert.Equal(t, []ComponentName{"lazy_db"}, cc.(ISnapshotContainer).Snapshot().Components[0].Path)
+	assert.Len(t, cc.(ISnapshotContainer).Snapshot().Components, 1)
+	assert.ErrorIs(t, cc.UnloadNamedComponents([]ComponentName{"broken"}, false), ErrComponentNameNotFound)
+
+	// 热重载失败回滚后新增组件的失败不再保留，热重载成功后不在配置中的失败组件不再保留
+	assert.Error(t, cc.(IReloadableContainer).ReloadNamedComponents([]ComponentConfig{{Name: "broken", Type: "db"}}))
+	assert.Len(t, cc.(ISnapshotContainer).Snapshot().Components, 1)
+	assert.Error(t, cc.LoadNamedComponents([]ComponentConfig{{Name: "broken", Type: "db"}}))
+	assert.Len(t, cc.(ISnapshotContainer).Snapshot().Components, 2)
+	assert.NoError(t, cc.(IReloadableContainer).ReloadNamedComponents([]ComponentConfig{{Name: "lazy_db", Type: "db", Lazy: true, Config: map[string]any{"url": "db://lazy"}}}))
+	assert.Len(t, cc.(ISnapshotContainer).Snapshot().Components, 1)
+}
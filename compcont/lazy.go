@@ -0,0 +1,108 @@
+package compcont
+
+import (
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// 懒加载组件在实例化之前放入容器的占位实例
+type lazyComponent struct {
+	mu sync.Mutex // 保证并发首次访问时只实例化一次
+}
+
+func isLazyPlaceholder(component Component) bool {
+	_, ok := component.Instance.(*lazyComponent)
+	return ok
+}
+
+// 组件是否为尚未实例化的懒加载组件
+func (c *ComponentContainer) isLazyPending(name ComponentName) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return isLazyPlaceholder(c.components[name])
+}
+
+// 校验组件的配置并放入占位实例，依赖、工厂和配置的解码在此时检查，实例在获取时才创建，用于懒加载和非单例的组件，
+// decoded为排序时已解码的配置，为空时在此解码
+func (c *ComponentContainer) loadDeferredComponent(config ComponentConfig, placeholderInstance any, decoded *decodedConfig) (err error) {
+	ctx := Context{Container: c, Config: config}
+	depContexts, err := c.checkDeps(config)
+	if err != nil {
+		return
+	}
+	factory, err := c.factoryRegistry.GetFactory(config.Type)
+	if err != nil {
+		return
+	}
+	if _, ok := factory.(IComponentConfigDecoder); ok {
+		if !decoded.reusable(config) {
+			decoded = decodeComponentConfig(c.factoryRegistry, config)
+		}
+		if err = decoded.err; err != nil {
+			err = fmt.Errorf("%w, component %s, %w", ErrComponentConfigInvalid, config.Name, err)
+			return
+		}
+	}
+
+	placeholder := Component{Context: ctx, Instance: placeholderInstance}
+	c.mu.Lock()
+	c.components[config.Name] = placeholder
+	c.mu.Unlock()
+
+	// 依赖关系在加载时即记录，以保证卸载和热重载的顺序
+	for _, depCtx := range depContexts {
+		recordDependency(ctx, depCtx)
+	}
+	for dep := range scanConfig(c.factoryRegistry, config, decoded).deps {
+		if c.isLoaded(dep) {
+			recordDependency(ctx, Context{Container: c, Config: ComponentConfig{Name: dep}})
+		}
+	}
+	return
+}
+
+// 实例化懒加载组件，创建失败时错误返回给调用方，下次访问时会重试
+func (c *ComponentContainer) instantiateLazy(name ComponentName, placeholder Component) (component Component, err error) {
+	lazy := placeholder.Instance.(*lazyComponent)
+	lazy.mu.Lock()
+	defer lazy.mu.Unlock()
+
+	c.mu.RLock()
+	current, ok := c.components[name]
+	c.mu.RUnlock()
+	if !ok || current.Instance != placeholder.Instance {
+		// 等待期间已被其他调用方实例化，或已被卸载、重载
+		if !ok || isLazyPlaceholder(current) {
+			return c.GetComponent(name)
+		}
+		return current, nil
+	}
+
+	component, err = c.loadComponent(placeholder.Context.Config, nil, nil)
+	if err != nil {
+		err = fmt.Errorf("instantiate lazy component %s failed, %w", name, err)
+		c.setStatus(name, func(status *componentStatus) { status.lastErr = err })
+		return
+	}
+
+	c.mu.Lock()
+	current, ok = c.components[name]
+	if ok && current.Instance == placeholder.Instance {
+		c.components[name] = component
+		if status, ok := c.statuses[name]; ok {
+			status.loadedAt = time.Now()
+		}
+		c.mu.Unlock()
+		return
+	}
+	c.mu.Unlock()
+	// 实例化期间组件已被卸载，新实例不再需要
+	if err1 := c.destroyComponent(name, component); err1 != nil {
+		c.logger.Error("destroy lazy component created after unloading failed", slog.String("component", string(name)), slog.Any("error", err1))
+	}
+	err = fmt.Errorf("%w, lazy component %s was unloaded while instantiating", ErrComponentNameNotFound, name)
+	component = Component{}
+	return
+}
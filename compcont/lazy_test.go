@@ -0,0 +1,84 @@
+package compcont
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLazyComponent(t *testing.T) {
+	var created atomic.Int32
+	r := newReloadRegistry()
+	MustRegister(r, &TypedSimpleComponentFactory[string, *reloadInstance]{
+		TypeID: "counted",
+		CreateInstanceFunc: func(ctx Context, config string) (instance *reloadInstance, err error) {
+			created.Add(1)
+			return &reloadInstance{config: config}, nil
+		},
+		DestroyInstanceFunc: func(ctx Context, instance *reloadInstance) (err error) {
+			instance.destroyed.Store(true)
+			return
+		},
+	})
+	cc := NewComponentContainer(WithFactoryRegistry(r))
+
+	// 加载时校验配置
+	err := cc.LoadNamedComponents([]ComponentConfig{{Name: "bad", Type: "counted", Config: map[string]any{"x": 1}, Lazy: true}})
+	assert.ErrorIs(t, err, ErrComponentConfigInvalid)
+
+	assert.NoError(t, cc.LoadNamedComponents([]ComponentConfig{
+		{Name: "a", Type: "counted", Config: "a", Lazy: true},
+		{Name: "b", Type: "counted", Deps: []ComponentName{"a"}, Config: "b", Lazy: true},
+		{Name: "f", Type: "reload", Config: "fail", Lazy: true},
+	}))
+	assert.Equal(t, int32(0), created.Load())
+
+	// 并发首次访问只实例化一次，只声明而未使用的懒加载依赖不会被实例化
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			component, err := GetComponent[*reloadInstance](cc, "b")
+			assert.NoError(t, err)
+			assert.Equal(t, "b", component.Instance.config)
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, int32(1), created.Load())
+
+	// 创建失败的错误返回给调用方
+	_, err = cc.GetComponent("f")
+	assert.Error(t, err)
+
+	a, err := GetComponent[*reloadInstance](cc, "a")
+	assert.NoError(t, err)
+	assert.Equal(t, int32(2), created.Load())
+	assert.NoError(t, cc.UnloadNamedComponents([]ComponentName{"a", "f"}, true))
+	assert.True(t, a.Instance.destroyed.Load())
+	assert.Empty(t, cc.LoadedComponentNames())
+}
+
+func TestLazyComponentLookup(t *testing.T) {
+	cc := NewComponentContainer(WithFactoryRegistry(newReloadRegistry()))
+	assert.NoError(t, cc.LoadNamedComponents([]ComponentConfig{
+		{Name: "a", Type: "reload", Config: "a"},
+		{Name: "b", Type: "reload", Config: "b", Lazy: true},
+		{Name: "f", Type: "reload", Config: "fail", Lazy: true},
+	}))
+
+	// 按类型查找不会实例化懒加载组件，其中创建失败的组件不影响查找
+	components, err := FindComponentsByType[*reloadInstance](cc)
+	assert.NoError(t, err)
+	assert.Len(t, components, 1)
+	assert.Equal(t, "a", components[0].Instance.config)
+
+	// 已实例化的懒加载组件可以被找到
+	_, err = cc.GetComponent("b")
+	assert.NoError(t, err)
+	components, err = FindComponentsByType[*reloadInstance](cc)
+	assert.NoError(t, err)
+	assert.Len(t, components, 2)
+}
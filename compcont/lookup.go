@@ -0,0 +1,186 @@
+package compcont
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+)
+
+type findOptions struct {
+	ancestors   bool
+	descendants bool
+	selector    Selector
+}
+
+type FindOptionsFunc func(o *findOptions)
+
+// FindInAncestors 查找时同时搜索所有祖先容器，结果中距离当前容器越近的越靠前
+func FindInAncestors() FindOptionsFunc {
+	return func(o *findOptions) {
+		o.ancestors = true
+	}
+}
+
+// FindInDescendants 查找时同时搜索子孙容器，子容器中的组件排在其所在的子容器组件之后，只会找到子孙容器导出的组件
+func FindInDescendants() FindOptionsFunc {
+	return func(o *findOptions) {
+		o.descendants = true
+	}
+}
+
+// WithSelector 只查找标签满足选择器的组件，通过refer引用的组件以引用处声明的标签为准
+func WithSelector(selector Selector) FindOptionsFunc {
+	return func(o *findOptions) {
+		o.selector = selector
+	}
+}
+
+// FindComponentsByType 在容器中查找所有实例可以赋值给Instance的组件，同一容器内按名称排序
+//
+// 通过refer引用的组件与被引用的组件是同一个实例，只会返回一次；scoped和transient组件以及尚未实例化的懒加载组件不参与查找，查找不会触发实例化
+func FindComponentsByType[Instance any](container IComponentContainer, optFns ...FindOptionsFunc) (ret []TypedComponent[Instance], err error) {
+	var opt findOptions
+	for _, fn := range optFns {
+		fn(&opt)
+	}
+
+	seen := make(map[componentKey]struct{})
+	var search func(current IComponentContainer, descended bool) error
+	search = func(current IComponentContainer, descended bool) error {
+		names := current.LoadedComponentNames()
+		slices.Sort(names)
+		for _, name := range names {
+			if descended && !isExported(current, name) {
+				// 子孙容器中未导出的组件对外不可见
+				continue
+			}
+			if c, ok := unwrapContainer[*ComponentContainer](current); ok && (!c.isSingleton(name) || c.isLazyPending(name)) {
+				// 非单例组件的实例与获取方式有关，懒加载组件的实例类型在实例化前未知，均不参与类型查找
+				continue
+			}
+			component, err := current.GetComponent(name)
+			if err != nil {
+				return err
+			}
+			k := componentKey{container: component.Context.Container, name: component.Context.Config.Name}
+			matched := opt.selector.Empty() || opt.selector.Matches(componentLabels(current, name))
+			if instance, ok := component.Instance.(Instance); ok && matched {
+				if _, ok := seen[k]; !ok {
+					seen[k] = struct{}{}
+					ret = append(ret, TypedComponent[Instance]{
+						Context:  component.Context,
+						Instance: instance,
+					})
+				}
+			}
+			// 只进入以该组件为父容器的子容器，引用得到的其他容器不重复搜索
+			if child, ok := component.Instance.(IComponentContainer); ok && opt.descendants && child.GetParent() == current {
+				if err = search(child, true); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+	for current := container; current != nil; current = current.GetParent() {
+		if err = search(current, false); err != nil {
+			return
+		}
+		if !opt.ancestors {
+			break
+		}
+	}
+	return
+}
+
+// 获取容器中具名组件声明的标签
+func componentLabels(container IComponentContainer, name ComponentName) map[string]string {
+	if c, ok := container.(*ComponentContainer); ok {
+		c.mu.RLock()
+		config, ok := c.configs[name]
+		c.mu.RUnlock()
+		if ok {
+			return config.Labels
+		}
+	}
+	component, err := container.GetComponent(name)
+	if err != nil {
+		return nil
+	}
+	return component.Context.Config.Labels
+}
+
+// FindOne 查找唯一一个实例可以赋值给Instance的组件，未找到或找到多个时报错
+func FindOne[Instance any](container IComponentContainer, optFns ...FindOptionsFunc) (ret TypedComponent[Instance], err error) {
+	components, err := FindComponentsByType[Instance](container, optFns...)
+	if err != nil {
+		return
+	}
+	switch len(components) {
+	case 0:
+		err = fmt.Errorf("%w, no component is assignable to %v", ErrComponentNameNotFound, reflect.TypeFor[Instance]())
+	case 1:
+		ret = components[0]
+	default:
+		var paths [][]ComponentName
+		for _, component := range components {
+			paths = append(paths, component.Context.GetAbsolutePath())
+		}
+		err = fmt.Errorf("%w, components %v are all assignable to %v", ErrComponentDependencyAmbiguous, paths, reflect.TypeFor[Instance]())
+	}
+	return
+}
+
+// MustFindOne 同 FindOne，出错时panic
+func MustFindOne[Instance any](container IComponentContainer, optFns ...FindOptionsFunc) TypedComponent[Instance] {
+	ret, err := FindOne[Instance](container, optFns...)
+	if err != nil {
+		panic(err)
+	}
+	return ret
+}
+
+// Collection 作为配置字段使用时，表示注入容器中所有实例可以赋值给Instance的组件
+//
+// 同一批加载的组件中标签满足选择器的组件会先于当前组件加载；未指定选择器时，被收集的组件需要在deps中声明，
+// 这些deps不会因为未在配置中引用而产生警告
+type Collection[Instance any] struct {
+	Enabled   bool            `ccf:"enabled"`   // 是否启用收集
+	Ancestors bool            `ccf:"ancestors"` // 是否同时收集祖先容器中的组件
+	Exclude   []ComponentName `ccf:"exclude"`   // 排除的组件名
+	Selector  Selector        `ccf:"selector"`  // 只收集标签满足选择器的组件，如 role=middleware, server=public
+}
+
+// collects implements componentCollector.
+func (c Collection[Instance]) collects() (selector Selector, exclude []ComponentName, enabled bool) {
+	return c.Selector, c.Exclude, c.Enabled
+}
+
+// Load 在组件构造时收集组件，ctx为正在构造的组件的上下文，当前组件自身不会被收集
+func (c Collection[Instance]) Load(ctx Context) (ret []TypedComponent[Instance], err error) {
+	if !c.Enabled {
+		return
+	}
+	var optFns []FindOptionsFunc
+	if c.Ancestors {
+		optFns = append(optFns, FindInAncestors())
+	}
+	if !c.Selector.Empty() {
+		optFns = append(optFns, WithSelector(c.Selector))
+	}
+	components, err := FindComponentsByType[Instance](ctx.Container, optFns...)
+	if err != nil {
+		return
+	}
+	for _, component := range components {
+		if component.Context.Container == ctx.Container {
+			name := component.Context.Config.Name
+			if name == ctx.Config.Name || slices.Contains(c.Exclude, name) {
+				continue
+			}
+		}
+		recordDependency(ctx, component.Context)
+		ret = append(ret, component)
+	}
+	return
+}
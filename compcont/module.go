@@ -0,0 +1,246 @@
+package compcont
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// Module 将一组组件工厂和默认的组件配置打包，例如预先配置好gin、recovery、zap中间件和prometheus的http模块
+//
+// 模块可以在代码中通过 LoadModule 加载，也可以通过 RegisterModule 注册后在配置中按名称引用
+type Module struct {
+	Name       string
+	Imports    []*Module           // 依赖的其他模块，其工厂和默认组件先于本模块注册和加载
+	Factories  []IComponentFactory // 模块提供的组件工厂
+	Aliases    []TypeAlias         // 模块提供的类型别名，在工厂之后注册
+	Components []ComponentConfig   // 模块默认的组件配置，使用方可以按名称覆盖
+	Exports    []ComponentName     // 模块以子容器加载时对外可见的组件，为nil时全部可见，见 WithExports
+}
+
+// FactoriesOf 收集注册函数注册的全部工厂，便于复用各个包中的 MustRegister 函数组装模块
+func FactoriesOf(registers ...func(registry IFactoryRegistry)) (factories []IComponentFactory) {
+	registry := NewFactoryRegistry()
+	for _, register := range registers {
+		register(registry)
+	}
+	types := registry.RegisteredComponentTypes()
+	slices.Sort(types)
+	for _, t := range types {
+		factory, _ := registry.GetFactory(t)
+		factories = append(factories, factory)
+	}
+	return
+}
+
+// AliasesOf 收集注册函数注册的全部类型别名，与 FactoriesOf 配合使用，使模块保留各个包中为兼容旧类型注册的别名
+func AliasesOf(registers ...func(registry IFactoryRegistry)) (aliases []TypeAlias) {
+	registry := NewFactoryRegistry()
+	for _, register := range registers {
+		register(registry)
+	}
+	aliases = RegisteredAliases(registry)
+	slices.SortFunc(aliases, func(a, b TypeAlias) int {
+		return strings.Compare(string(a.Alias), string(b.Alias))
+	})
+	return
+}
+
+// 按导入顺序展开模块及其依赖模块，每个模块只出现一次
+func (m *Module) flatten() (modules []*Module) {
+	visited := make(map[*Module]struct{})
+	var visit func(module *Module)
+	visit = func(module *Module) {
+		if _, ok := visited[module]; ok {
+			return
+		}
+		visited[module] = struct{}{}
+		for _, imported := range module.Imports {
+			visit(imported)
+		}
+		modules = append(modules, module)
+	}
+	visit(m)
+	return
+}
+
+// ImportedModules 模块直接和间接导入的全部模块名称，按导入顺序排列，不含模块自身
+func (m *Module) ImportedModules() (names []string) {
+	modules := m.flatten()
+	for _, module := range modules[:len(modules)-1] {
+		names = append(names, module.Name)
+	}
+	return
+}
+
+// Register 将模块及其依赖模块的工厂和类型别名注册到registry，同一个工厂或别名已注册时跳过，类型相同的其他工厂或别名已注册时报错
+func (m *Module) Register(registry IFactoryRegistry) (err error) {
+	for _, module := range m.flatten() {
+		for _, factory := range module.Factories {
+			registered, err1 := registry.GetFactory(factory.Type())
+			if err1 == nil {
+				if registered != factory {
+					err = fmt.Errorf("%w, module %s, component type: %s", ErrComponentTypeAlreadyRegistered, module.Name, factory.Type())
+					return
+				}
+				continue
+			}
+			if err = registry.Register(factory); err != nil {
+				err = fmt.Errorf("module %s register component type %s failed, %w", module.Name, factory.Type(), err)
+				return
+			}
+		}
+		for _, alias := range module.Aliases {
+			if registered, ok := GetAlias(registry, alias.Alias); ok {
+				if registered != alias {
+					err = fmt.Errorf("%w, module %s, alias: %s", ErrComponentTypeAlreadyRegistered, module.Name, alias.Alias)
+					return
+				}
+				continue
+			}
+			if err = RegisterAlias(registry, alias); err != nil {
+				err = fmt.Errorf("module %s register alias %s failed, %w", module.Name, alias, err)
+				return
+			}
+		}
+	}
+	return
+}
+
+// ComponentConfigs 合并模块及其依赖模块的默认组件配置与overrides
+//
+// overrides中与默认组件同名的配置覆盖其中非零值的字段，config都为map时逐个顶层字段覆盖，设置enabled为false可以禁用默认组件；
+// 不同名的配置作为新的组件追加在末尾
+func (m *Module) ComponentConfigs(overrides []ComponentConfig) (configs []ComponentConfig) {
+	index := make(map[ComponentName]int)
+	for _, module := range m.flatten() {
+		for _, config := range module.Components {
+			if i, ok := index[config.Name]; ok {
+				configs[i] = config
+				continue
+			}
+			index[config.Name] = len(configs)
+			configs = append(configs, config)
+		}
+	}
+	for _, override := range overrides {
+		i, ok := index[override.Name]
+		if !ok {
+			index[override.Name] = len(configs)
+			configs = append(configs, override)
+			continue
+		}
+		configs[i] = overrideModuleComponent(configs[i], override)
+	}
+	return
+}
+
+func overrideModuleComponent(base, override ComponentConfig) (config ComponentConfig) {
+	config = base
+	if override.Type != "" || override.Refer != "" || override.Template != "" {
+		// 更换了组件的来源时，默认的配置不再适用
+		config.Type, config.Refer, config.Template = override.Type, override.Refer, override.Template
+		config.Config, config.Params = nil, nil
+	}
+	if override.Deps != nil {
+		config.Deps = override.Deps
+	}
+	if override.Params != nil {
+		config.Params = override.Params
+	}
+	if override.Config != nil {
+		baseMap, baseOk := config.Config.(map[string]any)
+		overrideMap, overrideOk := override.Config.(map[string]any)
+		if baseOk && overrideOk {
+			merged := maps.Clone(baseMap)
+			maps.Copy(merged, overrideMap)
+			config.Config = merged
+		} else {
+			config.Config = override.Config
+		}
+	}
+	if override.Lazy {
+		config.Lazy = true
+	}
+	if override.Scope != "" {
+		config.Scope = override.Scope
+	}
+	if override.Enabled != nil {
+		config.Enabled = override.Enabled
+	}
+	if override.When != "" {
+		config.When = override.When
+	}
+	if override.Labels != nil {
+		config.Labels = override.Labels
+	}
+	if override.Annotations != nil {
+		config.Annotations = override.Annotations
+	}
+	return
+}
+
+// ModuleSnapshot 加载到容器中的模块，记录在容器快照中
+type ModuleSnapshot struct {
+	Name    string   `json:"name"`
+	Imports []string `json:"imports,omitempty"` // 直接和间接导入的模块，见 Module.ImportedModules
+}
+
+// LoadModule 注册模块的工厂，并将合并overrides后的组件加载到容器中，模块的名称和导入的模块记录在容器的快照中
+func LoadModule(container IComponentContainer, module *Module, overrides []ComponentConfig) (err error) {
+	if err = module.Register(container.FactoryRegistry()); err != nil {
+		return
+	}
+	if c, ok := unwrapContainer[*ComponentContainer](container); ok {
+		c.mu.Lock()
+		c.modules = append(c.modules, ModuleSnapshot{Name: module.Name, Imports: module.ImportedModules()})
+		c.mu.Unlock()
+	}
+	if err = container.LoadNamedComponents(module.ComponentConfigs(overrides)); err != nil {
+		err = fmt.Errorf("load module %s failed, %w", module.Name, err)
+	}
+	return
+}
+
+var (
+	modules   = make(map[string]*Module)
+	modulesMu sync.RWMutex
+)
+
+// RegisterModule 注册模块，使其可以在配置中按名称引用
+func RegisterModule(module *Module) (err error) {
+	modulesMu.Lock()
+	defer modulesMu.Unlock()
+	if _, ok := modules[module.Name]; ok {
+		err = fmt.Errorf("%w, module: %s", ErrModuleAlreadyRegistered, module.Name)
+		return
+	}
+	modules[module.Name] = module
+	return
+}
+
+func MustRegisterModule(module *Module) {
+	if err := RegisterModule(module); err != nil {
+		panic(err)
+	}
+}
+
+// GetModule 根据名称获取已注册的模块
+func GetModule(name string) (module *Module, err error) {
+	modulesMu.RLock()
+	defer modulesMu.RUnlock()
+	module, ok := modules[name]
+	if !ok {
+		err = fmt.Errorf("%w, module: %s", ErrModuleNotRegistered, name)
+	}
+	return
+}
+
+// RegisteredModules 全部已注册模块的名称
+func RegisteredModules() (names []string) {
+	modulesMu.RLock()
+	defer modulesMu.RUnlock()
+	return slices.Sorted(maps.Keys(modules))
+}
@@ -0,0 +1,73 @@
+package compcont
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestModule(t *testing.T) {
+	overrideFactory := &TypedSimpleComponentFactory[overrideConfig, overrideConfig]{
+		TypeID: "override",
+		CreateInstanceFunc: func(ctx Context, config overrideConfig) (instance overrideConfig, err error) {
+			return config, nil
+		},
+	}
+	base := &Module{
+		Name:      "base",
+		Factories: []IComponentFactory{overrideFactory},
+		Components: []ComponentConfig{
+			{Name: "db", Type: "override", Config: map[string]any{"url": "db://default", "port": 5432}},
+		},
+	}
+	disabled := false
+	app := &Module{
+		Name:      "app",
+		Imports:   []*Module{base},
+		Factories: []IComponentFactory{overrideFactory},
+		Components: []ComponentConfig{
+			{Name: "api", Type: "override", Deps: []ComponentName{"db"}, Config: map[string]any{"url": "http://default"}},
+			{Name: "debug", Type: "override"},
+		},
+	}
+	assert.Equal(t, []string{"base"}, app.ImportedModules())
+
+	r := NewFactoryRegistry()
+	assert.NoError(t, base.Register(r))
+	cc := NewComponentContainer(WithFactoryRegistry(r))
+	assert.NoError(t, LoadModule(cc, app, []ComponentConfig{
+		{Name: "db", Config: map[string]any{"port": 6432}},
+		{Name: "debug", Enabled: &disabled},
+		{Name: "extra", Type: "override"},
+	}))
+	db, err := GetComponent[overrideConfig](cc, "db")
+	assert.NoError(t, err)
+	assert.Equal(t, overrideConfig{URL: "db://default", Port: 6432}, db.Instance)
+	assert.ElementsMatch(t, []ComponentName{"db", "api", "extra"}, cc.LoadedComponentNames())
+
+	// 模块保留注册函数中注册的类型别名
+	alias := TypeAlias{Alias: "old-override", Target: "override", Deprecated: true}
+	aliased := &Module{
+		Name:      "aliased",
+		Factories: FactoriesOf(func(r IFactoryRegistry) { MustRegister(r, overrideFactory) }),
+		Aliases: AliasesOf(func(r IFactoryRegistry) {
+			MustRegister(r, overrideFactory)
+			assert.NoError(t, RegisterAlias(r, alias))
+		}),
+	}
+	assert.Equal(t, []TypeAlias{alias}, aliased.Aliases)
+	assert.NoError(t, aliased.Register(r))
+	assert.NoError(t, aliased.Register(r))
+	assert.NoError(t, LoadModule(cc, aliased, []ComponentConfig{{Name: "old", Type: "old-override"}}))
+	assert.Contains(t, cc.LoadedComponentNames(), ComponentName("old"))
+
+	// 同类型的其他工厂已注册时报错
+	assert.ErrorIs(t, (&Module{Name: "conflict", Factories: []IComponentFactory{&TypedSimpleComponentFactory[overrideConfig, overrideConfig]{TypeID: "override"}}}).Register(r), ErrComponentTypeAlreadyRegistered)
+
+	MustRegisterModule(app)
+	module, err := GetModule("app")
+	assert.NoError(t, err)
+	assert.Same(t, app, module)
+	_, err = GetModule("missing")
+	assert.ErrorIs(t, err, ErrModuleNotRegistered)
+}
@@ -0,0 +1,345 @@
+package compcont
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// 通过环境变量覆盖配置时使用的前缀，路径各级以双下划线分隔，如 COMPCONT__C1__REDIS__CONFIG__URL，需通过 WithEnvConfigOverrides 启用
+const OverrideEnvPrefix = "COMPCONT__"
+
+// ConfigOverride 按组件路径覆盖一个配置项，如 c1.redis.config.url 表示容器c1中redis组件配置的url字段
+//
+// 路径依次为各级组件名称、组件配置的字段名（config、type、refer、enabled、when、lazy、scope、template、params）以及config或params内部的字段名或列表下标，
+// 名称和字段名不区分大小写。组件名称之后的一级是上述字段名时作为该组件的字段，否则作为子容器中的组件名称，
+// 因此子容器中与字段同名的组件（如名为config的组件）无法被覆盖。值在解码前写入，会根据原有值的类型进行转换；
+// 原值不存在时依次尝试以字符串、bool、整数和浮点数写入，取第一个能被组件工厂解码的值，整数支持0x、0o和以0开头的八进制等前缀。
+// 没有匹配到任何组件的覆盖项在根容器加载完成后输出警告，也可以通过 CheckConfigOverrides 检查
+type ConfigOverride struct {
+	Path  []string
+	Value string
+}
+
+func (o ConfigOverride) String() string {
+	return strings.Join(o.Path, ".") + "=" + o.Value
+}
+
+// ParseConfigOverride 解析 path=value 形式的覆盖项
+func ParseConfigOverride(s string) (override ConfigOverride, err error) {
+	path, value, ok := strings.Cut(s, "=")
+	if !ok || path == "" {
+		err = fmt.Errorf("%w, override %q should be in form of path=value", ErrComponentConfigInvalid, s)
+		return
+	}
+	override = ConfigOverride{Path: strings.Split(path, "."), Value: value}
+	return
+}
+
+// ConfigOverridesFromEnv 从环境变量中解析覆盖项
+func ConfigOverridesFromEnv(environ []string) (overrides []ConfigOverride) {
+	for _, kv := range environ {
+		k, v, ok := strings.Cut(kv, "=")
+		if !ok || !strings.HasPrefix(k, OverrideEnvPrefix) {
+			continue
+		}
+		path := strings.Split(strings.ToLower(strings.TrimPrefix(k, OverrideEnvPrefix)), "__")
+		overrides = append(overrides, ConfigOverride{Path: path, Value: v})
+	}
+	return
+}
+
+// ConfigOverrideFlags 可以重复指定的命令行参数，如 flag.Var(&flags, "set", "override config, path=value")
+type ConfigOverrideFlags []ConfigOverride
+
+func (f *ConfigOverrideFlags) String() string {
+	var items []string
+	for _, o := range *f {
+		items = append(items, o.String())
+	}
+	return strings.Join(items, ",")
+}
+
+func (f *ConfigOverrideFlags) Set(s string) error {
+	override, err := ParseConfigOverride(s)
+	if err != nil {
+		return err
+	}
+	*f = append(*f, override)
+	return nil
+}
+
+// WithConfigOverrides 追加配置覆盖项，多个覆盖项按追加的顺序生效，子容器继承父容器的覆盖项
+func WithConfigOverrides(overrides ...ConfigOverride) optionsFunc {
+	return func(o *options) {
+		o.overrides = append(o.overrides, overrides...)
+	}
+}
+
+// WithEnvConfigOverrides 追加当前环境变量中以 OverrideEnvPrefix 开头的覆盖项，通常在命令行参数的覆盖项之前指定
+func WithEnvConfigOverrides() optionsFunc {
+	return WithConfigOverrides(ConfigOverridesFromEnv(os.Environ())...)
+}
+
+// 整个容器树中已经应用过的覆盖项，用于发现路径拼写错误等没有匹配到组件的覆盖项
+type appliedOverrides struct {
+	mu       sync.Mutex
+	keys     set[string] // 已应用的覆盖项
+	reported set[string] // 已输出过警告的未匹配覆盖项
+}
+
+func (a *appliedOverrides) add(override ConfigOverride) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.keys[override.key()] = struct{}{}
+}
+
+func (a *appliedOverrides) contains(override ConfigOverride) bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	_, ok := a.keys[override.key()]
+	return ok
+}
+
+// 不区分大小写的路径及覆盖的值
+func (o ConfigOverride) key() string {
+	return strings.ToLower(strings.Join(o.Path, ".")) + "=" + o.Value
+}
+
+// CheckConfigOverrides 检查指向container及其子孙容器的覆盖项是否都匹配到了组件，通常在加载全部组件后调用，
+// 尚未实例化的懒加载子容器中的组件视为未匹配
+func CheckConfigOverrides(container IComponentContainer) (err error) {
+	c, ok := unwrapContainer[*ComponentContainer](container)
+	if !ok {
+		return
+	}
+	if unmatched := c.unmatchedOverrides(); len(unmatched) > 0 {
+		err = fmt.Errorf("%w, config overrides %v do not match any component", ErrComponentConfigInvalid, unmatched)
+	}
+	return
+}
+
+// 指向该容器及其子孙容器但尚未应用过的覆盖项
+func (c *ComponentContainer) unmatchedOverrides() (unmatched []ConfigOverride) {
+	prefix := containerPath(c)
+	for _, override := range c.overrides {
+		if _, ok := trimPathPrefix(override.Path, prefix); ok && !c.appliedOverrides.contains(override) {
+			unmatched = append(unmatched, override)
+		}
+	}
+	return
+}
+
+// 对未匹配到组件的覆盖项输出警告，每个覆盖项只输出一次
+func (c *ComponentContainer) warnUnmatchedOverrides() {
+	for _, override := range c.unmatchedOverrides() {
+		c.appliedOverrides.mu.Lock()
+		_, reported := c.appliedOverrides.reported[override.key()]
+		c.appliedOverrides.reported[override.key()] = struct{}{}
+		c.appliedOverrides.mu.Unlock()
+		if !reported {
+			c.logger.Warn("config override does not match any loaded component", "override", override.String())
+		}
+	}
+}
+
+// 组件配置中可以被覆盖的字段
+var overridableFields = []string{"config", "type", "refer", "enabled", "when", "lazy", "scope", "template", "params"}
+
+// 将路径指向当前容器中组件的覆盖项应用到一批组件配置上，指向子容器中组件的覆盖项由子容器加载时应用
+func (c *ComponentContainer) applyOverrides(configs []ComponentConfig) (ret []ComponentConfig, err error) {
+	if len(c.overrides) == 0 {
+		return configs, nil
+	}
+	prefix := containerPath(c)
+	ret = make([]ComponentConfig, len(configs))
+	copy(ret, configs)
+	for _, override := range c.overrides {
+		path, ok := trimPathPrefix(override.Path, prefix)
+		if !ok {
+			continue
+		}
+		if len(path) < 2 {
+			if len(prefix) == 0 {
+				err = fmt.Errorf("%w, override %s should be in form of component.field", ErrComponentConfigInvalid, override)
+				return
+			}
+			// 指向该容器自身的覆盖项由父容器应用
+			continue
+		}
+		field := strings.ToLower(path[1])
+		if !slices.Contains(overridableFields, field) {
+			continue
+		}
+		for i := range ret {
+			if !strings.EqualFold(string(ret[i].Name), path[0]) {
+				continue
+			}
+			var decoder IComponentConfigDecoder
+			if factory, err1 := c.factoryRegistry.GetFactory(ret[i].Type); err1 == nil {
+				decoder, _ = factory.(IComponentConfigDecoder)
+			}
+			if err = overrideComponentConfig(&ret[i], field, path[2:], override.Value, decoder); err != nil {
+				err = fmt.Errorf("apply override %s failed, %w", override, err)
+				return
+			}
+			c.appliedOverrides.add(override)
+		}
+	}
+	return
+}
+
+func trimPathPrefix(path []string, prefix []ComponentName) (rest []string, ok bool) {
+	if len(path) < len(prefix) {
+		return
+	}
+	for i, name := range prefix {
+		if !strings.EqualFold(path[i], string(name)) {
+			return
+		}
+	}
+	return path[len(prefix):], true
+}
+
+// decoder为组件工厂的配置解码器，用于确定原值不存在时覆盖值的类型，可以为nil
+func overrideComponentConfig(config *ComponentConfig, field string, path []string, value string, decoder IComponentConfigDecoder) (err error) {
+	if field != "config" && field != "params" && len(path) > 0 {
+		return fmt.Errorf("%w, field %s has no sub field", ErrComponentConfigInvalid, field)
+	}
+	switch field {
+	case "config":
+		config.Config, err = overrideConfigValue(config.Config, path, value, decoder)
+	case "params":
+		var params any
+		if params, err = overrideValue(map[string]any(config.Params), path, coerceLeaf(value)); err == nil {
+			config.Params, _ = params.(map[string]any)
+		}
+	case "template":
+		config.Template = value
+	case "type":
+		config.Type = ComponentTypeID(value)
+	case "refer":
+		config.Refer = value
+	case "when":
+		config.When = value
+	case "scope":
+		config.Scope = ComponentScope(value)
+	case "enabled":
+		var enabled bool
+		enabled, err = strconv.ParseBool(value)
+		config.Enabled = &enabled
+	case "lazy":
+		config.Lazy, err = strconv.ParseBool(value)
+	}
+	return
+}
+
+// 覆盖组件配置中path指向的值，原值不存在时依次尝试以字符串、bool、整数和浮点数写入，取第一个能被decoder解码的结果
+func overrideConfigValue(current any, path []string, value string, decoder IComponentConfigDecoder) (ret any, err error) {
+	absent := false
+	ret, err = overrideValue(current, path, func(leaf any) (any, error) {
+		absent = leaf == nil
+		return coerceValue(leaf, value)
+	})
+	if err != nil || !absent || decoder == nil {
+		return
+	}
+	if _, err1 := decoder.DecodeConfig(ret); err1 == nil {
+		return
+	}
+	for _, candidate := range parseScalar(value) {
+		overridden, err1 := overrideValue(current, path, func(any) (any, error) { return candidate, nil })
+		if err1 != nil {
+			continue
+		}
+		if _, err1 = decoder.DecodeConfig(overridden); err1 == nil {
+			return overridden, nil
+		}
+	}
+	// 均无法解码时保留字符串，解码错误在组件构造时报告
+	return
+}
+
+// 将字符串按原值的类型写入的叶子节点
+func coerceLeaf(value string) func(leaf any) (any, error) {
+	return func(leaf any) (any, error) {
+		return coerceValue(leaf, value)
+	}
+}
+
+// 字符串可以表示的bool和数值
+func parseScalar(value string) (candidates []any) {
+	if b, err := strconv.ParseBool(value); err == nil {
+		candidates = append(candidates, b)
+	}
+	if i, err := strconv.ParseInt(value, 0, 64); err == nil {
+		candidates = append(candidates, int(i))
+	}
+	if f, err := strconv.ParseFloat(value, 64); err == nil {
+		candidates = append(candidates, f)
+	}
+	return
+}
+
+// 将leaf返回的值写入current中path指向的位置，返回写入后的值，map和列表会被复制而不修改原配置
+func overrideValue(current any, path []string, leaf func(current any) (any, error)) (ret any, err error) {
+	if len(path) == 0 {
+		return leaf(current)
+	}
+	switch v := current.(type) {
+	case nil:
+		child, err := overrideValue(nil, path[1:], leaf)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]any{path[0]: child}, nil
+	case map[string]any:
+		m := make(map[string]any, len(v)+1)
+		key := path[0]
+		for k, item := range v {
+			m[k] = item
+			if strings.EqualFold(k, path[0]) {
+				key = k
+			}
+		}
+		m[key], err = overrideValue(m[key], path[1:], leaf)
+		return m, err
+	case []any:
+		index, err := strconv.Atoi(path[0])
+		if err != nil || index < 0 || index >= len(v) {
+			return nil, fmt.Errorf("%w, invalid list index %s", ErrComponentConfigInvalid, path[0])
+		}
+		list := make([]any, len(v))
+		copy(list, v)
+		list[index], err = overrideValue(list[index], path[1:], leaf)
+		return list, err
+	default:
+		return nil, fmt.Errorf("%w, can not override field %s of %T, only map and list configs can be overridden", ErrComponentConfigInvalid, path[0], current)
+	}
+}
+
+// 根据原值的类型转换覆盖的值
+func coerceValue(current any, value string) (ret any, err error) {
+	switch current.(type) {
+	case string:
+		return value, nil
+	case bool:
+		return strconv.ParseBool(value)
+	case int:
+		i, err := strconv.ParseInt(value, 0, 64)
+		return int(i), err
+	case int64:
+		return strconv.ParseInt(value, 0, 64)
+	case uint64:
+		return strconv.ParseUint(value, 0, 64)
+	case float64:
+		return strconv.ParseFloat(value, 64)
+	case nil:
+		return value, nil
+	default:
+		return nil, fmt.Errorf("%w, can not override value of type %T", ErrComponentConfigInvalid, current)
+	}
+}
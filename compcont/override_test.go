@@ -0,0 +1,86 @@
+package compcont
+
+import (
+	"bytes"
+	"flag"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type overrideConfig struct {
+	URL     string `ccf:"url"`
+	Port    int    `ccf:"port"`
+	Enabled bool   `ccf:"enabled"`
+	Mode    uint32 `ccf:"mode"`
+}
+
+func TestConfigOverrides(t *testing.T) {
+	r := NewFactoryRegistry()
+	MustRegister(r, &TypedSimpleComponentFactory[overrideConfig, overrideConfig]{
+		TypeID: "override",
+		CreateInstanceFunc: func(ctx Context, config overrideConfig) (instance overrideConfig, err error) {
+			return config, nil
+		},
+	})
+
+	t.Setenv("COMPCONT__C1__REDIS__CONFIG__URL", "redis://env")
+	t.Setenv("COMPCONT__C1__REDIS__CONFIG__PORT", "6380")
+
+	var flags ConfigOverrideFlags
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.Var(&flags, "set", "override config")
+	assert.NoError(t, fs.Parse([]string{"--set", "c1.redis.config.url=redis://flag", "--set", "top.config.enabled=true", "--set", "top.config.mode=0644"}))
+
+	// 未指定时不应用环境变量中的覆盖项
+	plain := NewComponentContainer(WithFactoryRegistry(r))
+	assert.NoError(t, plain.LoadNamedComponents([]ComponentConfig{{Name: "c1", Type: "override", Config: map[string]any{"port": 6379}}}))
+	plainC1, err := GetComponent[overrideConfig](plain, "c1")
+	assert.NoError(t, err)
+	assert.Equal(t, 6379, plainC1.Instance.Port)
+
+	root := NewComponentContainer(WithFactoryRegistry(r), WithEnvConfigOverrides(), WithConfigOverrides(flags...))
+	assert.NoError(t, root.LoadNamedComponents([]ComponentConfig{
+		{Name: "top", Type: "override", Config: map[string]any{"url": "top"}},
+	}))
+	top, err := GetComponent[overrideConfig](root, "top")
+	assert.NoError(t, err)
+	// 原值不存在时按字段类型转换，整数支持八进制前缀
+	assert.Equal(t, overrideConfig{URL: "top", Enabled: true, Mode: 0o644}, top.Instance)
+
+	// 子容器中的组件同样可以被覆盖，命令行参数在环境变量之后生效
+	child := NewComponentContainer(
+		WithFactoryRegistry(r),
+		WithParentContainer(root),
+		WithContext(Context{Container: root, Config: ComponentConfig{Name: "c1"}}),
+	)
+	config := map[string]any{"url": "redis://file", "port": 6379, "mode": 0o600}
+	assert.NoError(t, child.LoadNamedComponents([]ComponentConfig{{Name: "redis", Type: "override", Config: config}}))
+	redis, err := GetComponent[overrideConfig](child, "redis")
+	assert.NoError(t, err)
+	assert.Equal(t, overrideConfig{URL: "redis://flag", Port: 6380, Mode: 0o600}, redis.Instance)
+	// 原配置不会被修改
+	assert.Equal(t, "redis://file", config["url"])
+
+	// 全部覆盖项都匹配到了组件
+	assert.NoError(t, CheckConfigOverrides(root))
+
+	// 路径拼写错误的覆盖项没有匹配到组件
+	typo, err := ParseConfigOverride("tpo.config.url=x")
+	assert.NoError(t, err)
+	var logs bytes.Buffer
+	cc := NewComponentContainer(WithFactoryRegistry(r), WithConfigOverrides(typo), WithLogger(slog.New(slog.NewTextHandler(&logs, nil))))
+	assert.NoError(t, cc.LoadNamedComponents([]ComponentConfig{{Name: "top", Type: "override"}}))
+	assert.Contains(t, logs.String(), "tpo.config.url=x")
+	err = CheckConfigOverrides(cc)
+	assert.ErrorIs(t, err, ErrComponentConfigInvalid)
+	assert.ErrorContains(t, err, "tpo.config.url=x")
+
+	// 根容器中的覆盖项至少需要组件名和字段名
+	cc = NewComponentContainer(WithFactoryRegistry(r), WithConfigOverrides(ConfigOverride{Path: []string{"top"}, Value: "x"}))
+	assert.ErrorIs(t, cc.LoadNamedComponents([]ComponentConfig{{Name: "top", Type: "override"}}), ErrComponentConfigInvalid)
+
+	_, err = ParseConfigOverride("no-value")
+	assert.ErrorIs(t, err, ErrComponentConfigInvalid)
+}
@@ -0,0 +1,238 @@
+package compcont
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// 一次组件加载的耗时记录
+type ComponentProfile struct {
+	Path   []ComponentName   // 组件的绝对路径，匿名组件的最后一级为空
+	TypeID ComponentTypeID   // 组件类型，引用组件为空
+	Deps   [][]ComponentName // 所依赖组件的绝对路径
+	Begin  time.Time
+	End    time.Time
+	Decode time.Duration // 解码配置耗时
+	Create time.Duration // 创建实例耗时，包含在创建期间加载的匿名组件和子容器中组件的耗时
+	Start  time.Duration // 启动实例耗时
+	Err    error
+}
+
+func (p ComponentProfile) Total() time.Duration {
+	return p.End.Sub(p.Begin)
+}
+
+func (p ComponentProfile) String() string {
+	return formatPath(p.Path)
+}
+
+// Profiler 默认最多保留的耗时记录数
+const DefaultProfilerCapacity = 1024
+
+// Profiler 记录容器及其子容器中所有组件（包括匿名组件）的加载耗时
+//
+// 热重载、懒加载以及scoped和transient组件的每次创建都会产生记录，超过容量后丢弃最早的记录，
+// 只关心启动耗时时可以在启动完成后调用 Stop
+type Profiler struct {
+	mu       sync.Mutex
+	profiles []ComponentProfile
+	capacity int
+	next     int // 记录已满时下一条要覆盖的位置
+	stopped  bool
+}
+
+func NewProfiler() *Profiler {
+	return NewProfilerWithCapacity(DefaultProfilerCapacity)
+}
+
+// NewProfilerWithCapacity 创建一个最多保留capacity条记录的Profiler
+func NewProfilerWithCapacity(capacity int) *Profiler {
+	return &Profiler{capacity: max(capacity, 1)}
+}
+
+func (p *Profiler) record(profile ComponentProfile) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.stopped {
+		return
+	}
+	if len(p.profiles) < p.capacity {
+		p.profiles = append(p.profiles, profile)
+		return
+	}
+	// 已满时覆盖最早的记录，Profiles 会重新按开始时间排序
+	p.profiles[p.next] = profile
+	p.next = (p.next + 1) % p.capacity
+}
+
+// Stop 停止记录，已有的记录保留
+func (p *Profiler) Stop() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.stopped = true
+}
+
+// Profiles 获取全部耗时记录，按开始时间排序
+func (p *Profiler) Profiles() (profiles []ComponentProfile) {
+	p.mu.Lock()
+	profiles = slices.Clone(p.profiles)
+	p.mu.Unlock()
+	slices.SortStableFunc(profiles, func(a, b ComponentProfile) int {
+		return a.Begin.Compare(b.Begin)
+	})
+	return
+}
+
+// 启动耗时报告
+type ProfileReport struct {
+	Components   []ComponentProfile // 按开始时间排序的全部耗时记录
+	CriticalPath []ComponentProfile // 关键路径，从最先开始加载的组件到最后完成的组件
+	Self         []time.Duration    // 关键路径上每个组件自身的耗时，不含其创建期间嵌套加载的组件
+	Total        time.Duration      // 关键路径的总耗时
+}
+
+// Report 生成启动耗时报告
+//
+// 组件在创建期间嵌套加载的匿名组件和子容器中的组件被视为该组件的前置节点，与依赖的组件一起构成依赖图，
+// 关键路径是该图中自身耗时之和最长的路径。嵌套关系根据加载时间区间的包含关系判断，因此要求组件是顺序加载的
+func (p *Profiler) Report() (report ProfileReport) {
+	profiles := p.Profiles()
+	report.Components = profiles
+	if len(profiles) == 0 {
+		return
+	}
+
+	// 每个路径对应最后一次加载的记录，用于解析依赖
+	byPath := make(map[string]int)
+	for i, profile := range profiles {
+		if profile.Path[len(profile.Path)-1] != "" {
+			byPath[formatPath(profile.Path)] = i
+		}
+	}
+
+	// 嵌套关系：包含当前记录时间区间的最小区间的记录即为其父节点
+	parent := make([]int, len(profiles))
+	self := make([]time.Duration, len(profiles))
+	for i, profile := range profiles {
+		parent[i] = -1
+		self[i] = profile.Total()
+		for j, candidate := range profiles {
+			if i == j || candidate.Begin.After(profile.Begin) || candidate.End.Before(profile.End) {
+				continue
+			}
+			if candidate.Begin.Equal(profile.Begin) && candidate.End.Equal(profile.End) && j < i {
+				// 区间相同时，后完成记录的是外层组件
+				continue
+			}
+			if parent[i] == -1 || profiles[parent[i]].Total() > candidate.Total() {
+				parent[i] = j
+			}
+		}
+	}
+	preds := make([][]int, len(profiles))
+	for i, profile := range profiles {
+		if parent[i] != -1 {
+			self[parent[i]] -= profile.Total()
+			preds[parent[i]] = append(preds[parent[i]], i)
+		}
+		for _, dep := range profile.Deps {
+			if j, ok := byPath[formatPath(dep)]; ok && j != i && !profiles[j].End.After(profile.Begin) {
+				preds[i] = append(preds[i], j)
+			}
+		}
+	}
+
+	// 前置节点总是先于当前节点完成，按完成时间顺序计算最长路径
+	orders := make([]int, len(profiles))
+	for i := range orders {
+		orders[i] = i
+	}
+	slices.SortStableFunc(orders, func(a, b int) int {
+		return profiles[a].End.Compare(profiles[b].End)
+	})
+	finish := make([]time.Duration, len(profiles))
+	prev := make([]int, len(profiles))
+	last := -1
+	for _, i := range orders {
+		self[i] = max(self[i], 0)
+		prev[i] = -1
+		for _, j := range preds[i] {
+			if prev[i] == -1 || finish[j] > finish[prev[i]] {
+				prev[i] = j
+			}
+		}
+		finish[i] = self[i]
+		if prev[i] != -1 {
+			finish[i] += finish[prev[i]]
+		}
+		if last == -1 || finish[i] > finish[last] {
+			last = i
+		}
+	}
+
+	report.Total = finish[last]
+	for i := last; i != -1; i = prev[i] {
+		report.CriticalPath = append(report.CriticalPath, profiles[i])
+		report.Self = append(report.Self, self[i])
+	}
+	slices.Reverse(report.CriticalPath)
+	slices.Reverse(report.Self)
+	return
+}
+
+func (r ProfileReport) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "critical path: %s\n", r.Total)
+	for i, profile := range r.CriticalPath {
+		fmt.Fprintf(&b, "  %-40s %-30s self=%s total=%s\n", profile, profile.TypeID, r.Self[i], profile.Total())
+	}
+	b.WriteString("components:\n")
+	for _, profile := range r.Components {
+		fmt.Fprintf(&b, "  %-40s %-30s decode=%s create=%s start=%s total=%s", profile, profile.TypeID, profile.Decode, profile.Create, profile.Start, profile.Total())
+		if profile.Err != nil {
+			fmt.Fprintf(&b, " error=%v", profile.Err)
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// WithProfiler 记录容器中组件的加载耗时，不指定时继承父容器的Profiler
+func WithProfiler(profiler *Profiler) optionsFunc {
+	return func(o *options) {
+		o.profiler = profiler
+	}
+}
+
+// Profiler 容器使用的Profiler，未指定时为nil
+func (c *ComponentContainer) Profiler() *Profiler {
+	return c.profiler
+}
+
+// 组件加载完成时所依赖组件的绝对路径
+func (c *ComponentContainer) profileDeps(name ComponentName, depContexts []Context) (deps [][]ComponentName) {
+	for _, depCtx := range depContexts {
+		deps = append(deps, depCtx.GetAbsolutePath())
+	}
+	if name == "" {
+		return
+	}
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	for key := range c.dependencies[name] {
+		deps = append(deps, (&Context{Container: key.container, Config: ComponentConfig{Name: key.name}}).GetAbsolutePath())
+	}
+	return
+}
+
+func formatPath(path []ComponentName) string {
+	var b strings.Builder
+	for _, name := range path {
+		b.WriteString("/")
+		b.WriteString(string(name))
+	}
+	return b.String()
+}
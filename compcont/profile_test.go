@@ -0,0 +1,67 @@
+package compcont
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProfiler(t *testing.T) {
+	r := NewFactoryRegistry()
+	MustRegister(r, &TypedSimpleComponentFactory[time.Duration, time.Duration]{
+		TypeID: "sleep",
+		CreateInstanceFunc: func(ctx Context, config time.Duration) (instance time.Duration, err error) {
+			time.Sleep(config)
+			return config, nil
+		},
+		StartInstanceFunc: func(ctx Context, instance time.Duration) (err error) {
+			time.Sleep(instance)
+			return
+		},
+	})
+	profiler := NewProfiler()
+	cc := NewComponentContainer(WithFactoryRegistry(r), WithProfiler(profiler))
+	assert.NoError(t, cc.LoadNamedComponents([]ComponentConfig{
+		{Name: "a", Type: "sleep", Config: 20 * time.Millisecond},
+		{Name: "b", Type: "sleep", Deps: []ComponentName{"a"}, Config: 10 * time.Millisecond},
+		{Name: "c", Type: "sleep", Config: 5 * time.Millisecond},
+	}))
+
+	report := profiler.Report()
+	assert.Len(t, report.Components, 3)
+	var path []string
+	for _, profile := range report.CriticalPath {
+		path = append(path, profile.String())
+	}
+	assert.Equal(t, []string{"/a", "/b"}, path)
+	assert.GreaterOrEqual(t, report.Total, 60*time.Millisecond)
+	assert.GreaterOrEqual(t, report.CriticalPath[0].Start, 20*time.Millisecond)
+}
+
+func TestProfilerCapacity(t *testing.T) {
+	r := NewFactoryRegistry()
+	MustRegister(r, &TypedSimpleComponentFactory[string, string]{
+		TypeID: "echo",
+		CreateInstanceFunc: func(ctx Context, config string) (instance string, err error) {
+			return config, nil
+		},
+	})
+	profiler := NewProfilerWithCapacity(2)
+	cc := NewComponentContainer(WithFactoryRegistry(r), WithProfiler(profiler))
+	assert.NoError(t, cc.LoadNamedComponents([]ComponentConfig{{Name: "a", Type: "echo"}}))
+	assert.NoError(t, cc.LoadNamedComponents([]ComponentConfig{{Name: "b", Type: "echo"}}))
+	assert.NoError(t, cc.LoadNamedComponents([]ComponentConfig{{Name: "c", Type: "echo"}}))
+
+	// 超过容量后丢弃最早的记录
+	profiles := profiler.Profiles()
+	assert.Len(t, profiles, 2)
+	assert.Equal(t, "/b", profiles[0].String())
+	assert.Equal(t, "/c", profiles[1].String())
+
+	// 停止后不再记录
+	profiler.Stop()
+	_, err := cc.LoadAnonymousComponent(ComponentConfig{Type: "echo"})
+	assert.NoError(t, err)
+	assert.Len(t, profiler.Profiles(), 2)
+}
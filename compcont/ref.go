@@ -0,0 +1,67 @@
+package compcont
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Ref 一个指向已加载组件的类型化引用，可直接作为配置字段使用
+//
+// 在配置中以纯字符串路径表示，路径语法与 ComponentConfig.Refer 相同，如 "redis"、"../shared/logger"、"/c1/redis"，
+// 为兼容由 TypedComponentConfig 改为Ref的字段，也可以写作 { refer: "redis" }
+type Ref[Instance any] struct {
+	Path string
+}
+
+func NewRef[Instance any](path string) Ref[Instance] {
+	return Ref[Instance]{Path: path}
+}
+
+// IsSet 是否配置了引用路径
+func (r Ref[Instance]) IsSet() bool {
+	return r.Path != ""
+}
+
+func (r Ref[Instance]) String() string {
+	return r.Path
+}
+
+func (r Ref[Instance]) referPath() string {
+	return r.Path
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler，使其可以从json、yaml以及组件配置中的字符串解码
+func (r *Ref[Instance]) UnmarshalText(text []byte) error {
+	r.Path = string(text)
+	return nil
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (r Ref[Instance]) MarshalText() ([]byte, error) {
+	return []byte(r.Path), nil
+}
+
+// Load 在组件构造时解析引用，ctx为正在构造的组件的上下文，引用路径相对于其所在容器
+//
+// 解析成功后会记录一条ctx对应组件到被引用组件的依赖关系
+func (r Ref[Instance]) Load(ctx Context) (ret TypedComponent[Instance], err error) {
+	if r.Path == "" {
+		err = fmt.Errorf("%w, ref path is empty", ErrComponentConfigInvalid)
+		return
+	}
+	component, err := resolveReferInScope(referBase(ctx.Container, ctx.Config.Name, r.Path), r.Path, ctx.Scope)
+	if err != nil {
+		return
+	}
+	instance, ok := component.Instance.(Instance)
+	if !ok {
+		err = fmt.Errorf("load ref failed, %w, path: %s, component type: %s, expected instance type %v, but got %v", ErrComponentTypeMismatch, r.Path, component.Context.Config.Type, reflect.TypeFor[Instance](), reflect.TypeOf(component.Instance))
+		return
+	}
+	recordDependency(ctx, component.Context)
+	ret = TypedComponent[Instance]{
+		Context:  component.Context,
+		Instance: instance,
+	}
+	return
+}
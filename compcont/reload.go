@@ -0,0 +1,446 @@
+package compcont
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"reflect"
+	"slices"
+	"time"
+)
+
+// 热重载时被摘除的旧组件，用于重载失败时回滚或重载成功后销毁
+type detachedComponent struct {
+	component    Component
+	config       ComponentConfig
+	status       *componentStatus
+	dependencies set[componentKey]
+	dependents   set[componentKey]
+}
+
+// ReloadNamedComponents 以configs作为容器新的完整组件配置进行热重载
+//
+// 与当前配置相比发生变化或被删除的组件，以及直接或间接依赖它们的组件会按依赖顺序重新构建，新增的组件会被加载，
+// 未受影响的组件保持不变。任何组件构建失败时，已构建的新组件会被销毁并恢复全部旧组件；全部构建成功后才切换句柄并销毁旧组件
+func (c *ComponentContainer) ReloadNamedComponents(configs []ComponentConfig) (err error) {
+	c.reloadMu.Lock()
+	defer c.reloadMu.Unlock()
+
+	start := time.Now()
+	c.emit(Event{Type: EventContainerReloading, Path: containerPath(c)})
+	defer func() {
+		c.emit(Event{Type: EventContainerReloaded, Path: containerPath(c), Duration: time.Since(start), Err: err})
+	}()
+
+	configs, err = c.applyOverrides(configs)
+	if err != nil {
+		return
+	}
+	configs, disabled, err := c.filterEnabled(configs)
+	if err != nil {
+		return
+	}
+	defer func() {
+		if err == nil {
+			c.setDisabled(configs, disabled, true)
+			// 新配置是容器完整的配置，之前创建失败的组件要么已重新加载，要么已不在配置中
+			c.clearFailed(func(ComponentName) bool { return true })
+		}
+	}()
+	return c.reloadConfigs(configs, nil)
+}
+
+// RebuildComponents implements IRebuildableContainer，以当前的配置重新构建指定的组件以及直接或间接依赖它们的组件，过程与热重载相同，任何组件构建失败时回滚
+func (c *ComponentContainer) RebuildComponents(names []ComponentName) (err error) {
+	c.reloadMu.Lock()
+	defer c.reloadMu.Unlock()
+
+	start := time.Now()
+	c.emit(Event{Type: EventContainerReloading, Path: containerPath(c)})
+	defer func() {
+		c.emit(Event{Type: EventContainerReloaded, Path: containerPath(c), Duration: time.Since(start), Err: err})
+	}()
+
+	forced := make(set[ComponentName])
+	c.mu.RLock()
+	configs := make([]ComponentConfig, 0, len(c.configs))
+	for _, cfg := range c.configs {
+		configs = append(configs, cfg)
+	}
+	for _, name := range names {
+		if _, ok := c.configs[name]; !ok {
+			c.mu.RUnlock()
+			return fmt.Errorf("%w, component %s is not loaded from config and can not be rebuilt", ErrComponentNameNotFound, name)
+		}
+		forced[name] = struct{}{}
+	}
+	c.mu.RUnlock()
+	return c.reloadConfigs(configs, forced)
+}
+
+// 以configs作为新的完整组件配置进行热重载，forced中的组件即使配置没有变化也会被重建
+func (c *ComponentContainer) reloadConfigs(configs []ComponentConfig, forced set[ComponentName]) (err error) {
+	newConfigs := make(map[ComponentName]ComponentConfig)
+	for _, cfg := range configs {
+		newConfigs[cfg.Name] = cfg
+	}
+
+	c.mu.RLock()
+	oldConfigs := make(map[ComponentName]ComponentConfig, len(c.configs))
+	for name, cfg := range c.configs {
+		oldConfigs[name] = cfg
+	}
+	for name := range newConfigs {
+		if _, ok := c.components[name]; ok {
+			if _, ok := c.configs[name]; !ok {
+				c.mu.RUnlock()
+				return fmt.Errorf("%w, component %s was put directly and can not be reloaded", ErrComponentAlreadyExists, name)
+			}
+		}
+	}
+	c.mu.RUnlock()
+
+	// 找出发生变化的组件及受其影响的组件
+	affected := make(set[ComponentName])
+	for name := range forced {
+		affected[name] = struct{}{}
+	}
+	for name, oldCfg := range oldConfigs {
+		if newCfg, ok := newConfigs[name]; !ok || !configEqual(oldCfg, newCfg) {
+			affected[name] = struct{}{}
+		}
+	}
+	affected, err = c.affectedClosure(affected)
+	if err != nil {
+		return
+	}
+
+	// 需要构建的组件：受影响且仍存在的组件以及新增的组件
+	var buildConfigs []ComponentConfig
+	for _, cfg := range configs {
+		_, isAffected := affected[cfg.Name]
+		_, isOld := oldConfigs[cfg.Name]
+		if isAffected || !isOld {
+			buildConfigs = append(buildConfigs, cfg)
+		}
+	}
+	if len(affected) == 0 && len(buildConfigs) == 0 {
+		return
+	}
+	// 摘除旧组件前先排序，尽早发现依赖关系的错误
+	existing := func(name ComponentName) bool {
+		_, isAffected := affected[name]
+		return !isAffected && c.isLoaded(name)
+	}
+	_, orders, _, err := c.sortComponents(buildConfigs, existing)
+	if err != nil {
+		return
+	}
+
+	c.logger.Info("reloading components",
+		slog.Any("container", containerPath(c)),
+		slog.Any("affected", sortedNames(affected)),
+		slog.Any("orders", orders),
+	)
+
+	// 摘除旧组件，新组件构建完成前旧组件不会被销毁；引用了被重建的模板的组件在模板重建后才能展开配置并排序
+	detached := c.detachComponents(affected)
+
+	built, err := c.loadSorted(buildConfigs, existing, func(config ComponentConfig, decoded *decodedConfig) (err error) {
+		if err = c.loadNamedComponent(config, decoded); err != nil {
+			err = fmt.Errorf("reload component %s failed, changes are rolled back, %w", config.Name, err)
+		}
+		return
+	})
+	if err != nil {
+		c.rollbackReload(built, detached)
+		// 回滚后容器恢复到重载前的状态，本次新增的组件的失败不再保留
+		c.clearFailed(func(name ComponentName) bool {
+			_, ok := newConfigs[name]
+			return ok
+		})
+		return
+	}
+
+	// 已有句柄的懒加载组件需要立即实例化以切换句柄
+	for _, name := range built {
+		c.mu.RLock()
+		hasHandles := len(c.handles[name]) > 0
+		c.mu.RUnlock()
+		if !hasHandles {
+			continue
+		}
+		if _, err = c.GetComponent(name); err != nil {
+			err = fmt.Errorf("reload component %s failed, changes are rolled back, %w", name, err)
+			c.rollbackReload(built, detached)
+			return
+		}
+	}
+
+	// 新实例需要满足已有句柄的类型要求，否则回滚
+	c.mu.Lock()
+	for _, name := range built {
+		for typ, h := range c.handles[name] {
+			if !h.accepts(c.components[name]) {
+				c.mu.Unlock()
+				err = fmt.Errorf("reload component %s failed, changes are rolled back, %w, new instance %T is not assignable to handle type %v", name, ErrComponentTypeMismatch, c.components[name].Instance, typ)
+				c.rollbackReload(built, detached)
+				return
+			}
+		}
+	}
+	// 全部构建成功，将句柄切换到新实例，已被删除的组件的句柄不再指向任何实例
+	var drained []<-chan struct{}
+	for _, name := range built {
+		for _, h := range c.handles[name] {
+			drained = append(drained, h.replace(c.components[name]))
+		}
+	}
+	for name := range detached {
+		if _, ok := newConfigs[name]; !ok {
+			for _, h := range c.handles[name] {
+				drained = append(drained, h.remove())
+			}
+			delete(c.handles, name)
+		}
+	}
+	c.mu.Unlock()
+
+	c.retireDetached(detached, drained)
+	return
+}
+
+// 销毁被替换的旧组件，若旧实例仍有通过句柄获取的使用者，则在后台等待其release或宽限期结束后再销毁
+func (c *ComponentContainer) retireDetached(detached map[ComponentName]detachedComponent, drained []<-chan struct{}) {
+	destroy := func() {
+		// 按依赖关系的逆序销毁旧组件
+		var destroyErrs []error
+		for _, name := range c.detachedDestroyOrder(detached) {
+			if err := c.destroyComponent(name, detached[name].component); err != nil {
+				destroyErrs = append(destroyErrs, fmt.Errorf("destroy old component %s failed, %w", name, err))
+			}
+		}
+		if len(destroyErrs) > 0 {
+			// 新组件已经生效，销毁旧组件的失败不再回滚，仅记录
+			c.logger.Error("destroy old components after reloading failed", slog.Any("error", errors.Join(destroyErrs...)))
+		}
+	}
+
+	pending := slices.DeleteFunc(drained, func(ch <-chan struct{}) bool {
+		select {
+		case <-ch:
+			return true
+		default:
+			return false
+		}
+	})
+	if len(pending) == 0 {
+		destroy()
+		return
+	}
+
+	go func() {
+		timer := time.NewTimer(c.handleGracePeriod)
+		defer timer.Stop()
+		for _, ch := range pending {
+			select {
+			case <-ch:
+			case <-timer.C:
+				c.logger.Warn("old instances are still in use after grace period, destroy them anyway",
+					slog.Any("container", containerPath(c)),
+					slog.Duration("grace_period", c.handleGracePeriod),
+				)
+				destroy()
+				return
+			}
+		}
+		destroy()
+	}()
+}
+
+// 计算受影响组件的闭包：依赖受影响组件的组件同样受影响，子容器中的组件依赖受影响组件时，整个子容器受影响
+func (c *ComponentContainer) affectedClosure(changed set[ComponentName]) (affected set[ComponentName], err error) {
+	affected = make(set[ComponentName])
+	var queue []ComponentName
+	for name := range changed {
+		affected[name] = struct{}{}
+		queue = append(queue, name)
+	}
+	for len(queue) > 0 {
+		name := queue[0]
+		queue = queue[1:]
+
+		c.mu.RLock()
+		var dependents []componentKey
+		for key := range c.dependents[name] {
+			dependents = append(dependents, key)
+		}
+		c.mu.RUnlock()
+
+		for _, key := range dependents {
+			dependentName, ok := c.localAncestorOf(key)
+			if !ok {
+				err = fmt.Errorf("%w, component %s is required by %v outside the reloading container", ErrComponentHasDependents, name, (&Context{Container: key.container, Config: ComponentConfig{Name: key.name}}).GetAbsolutePath())
+				return
+			}
+			if _, ok := affected[dependentName]; ok {
+				continue
+			}
+			affected[dependentName] = struct{}{}
+			queue = append(queue, dependentName)
+		}
+	}
+	return
+}
+
+// 找到key对应的组件在当前容器中的祖先组件，即组件本身或包含该组件的子容器
+func (c *ComponentContainer) localAncestorOf(key componentKey) (name ComponentName, ok bool) {
+	if key.container == IComponentContainer(c) {
+		return key.name, true
+	}
+	for current := key.container; current != nil; current = current.GetParent() {
+		if current.GetParent() == IComponentContainer(c) {
+			return current.GetContext().Config.Name, true
+		}
+	}
+	return
+}
+
+// 从容器中摘除一批组件及其依赖关系
+func (c *ComponentContainer) detachComponents(names set[ComponentName]) (detached map[ComponentName]detachedComponent) {
+	detached = make(map[ComponentName]detachedComponent)
+	for name := range names {
+		c.mu.Lock()
+		d := detachedComponent{
+			component: c.components[name],
+			config:    c.configs[name],
+			status:    c.statuses[name],
+		}
+		delete(c.components, name)
+		delete(c.configs, name)
+		delete(c.statuses, name)
+		c.mu.Unlock()
+		d.dependencies, d.dependents = c.removeEdges(name)
+		detached[name] = d
+	}
+	return
+}
+
+// 销毁已构建的新组件并恢复旧组件
+func (c *ComponentContainer) rollbackReload(built []ComponentName, detached map[ComponentName]detachedComponent) {
+	for _, name := range slices.Backward(built) {
+		c.mu.Lock()
+		component := c.components[name]
+		delete(c.components, name)
+		delete(c.configs, name)
+		delete(c.statuses, name)
+		c.mu.Unlock()
+		c.removeEdges(name)
+		if err := c.destroyComponent(name, component); err != nil {
+			c.logger.Error("destroy component failed while rolling back reload", slog.String("component", string(name)), slog.Any("error", err))
+		}
+	}
+	for name, d := range detached {
+		c.mu.Lock()
+		c.components[name] = d.component
+		c.configs[name] = d.config
+		if d.status != nil {
+			// 恢复旧组件的状态，保留本次重载失败的错误
+			if current, ok := c.statuses[name]; ok && current.lastErr != nil {
+				d.status.lastErr = current.lastErr
+			}
+			c.statuses[name] = d.status
+		}
+		c.mu.Unlock()
+		c.restoreEdges(name, d.dependencies, d.dependents)
+	}
+}
+
+// 被摘除的旧组件的销毁顺序，依赖其他组件的组件先销毁
+func (c *ComponentContainer) detachedDestroyOrder(detached map[ComponentName]detachedComponent) []ComponentName {
+	dag := make(map[ComponentName]set[ComponentName])
+	for name, d := range detached {
+		dag[name] = make(set[ComponentName])
+		for key := range d.dependencies {
+			if _, ok := detached[key.name]; ok && key.container == IComponentContainer(c) {
+				dag[name][key.name] = struct{}{}
+			}
+		}
+	}
+	orders, err := topologicalSort(dag)
+	if err != nil {
+		// 旧组件之间不应存在环，兜底按名称顺序销毁
+		names := make(set[ComponentName])
+		for name := range detached {
+			names[name] = struct{}{}
+		}
+		return sortedNames(names)
+	}
+	slices.Reverse(orders)
+	return orders
+}
+
+// 比较两份组件配置是否相同，config和params中的数值按值比较，如JSON解码得到的float64与YAML解码得到的int
+func configEqual(a, b ComponentConfig) bool {
+	a.Config, b.Config = normalizeNumbers(a.Config), normalizeNumbers(b.Config)
+	if a.Params != nil {
+		a.Params = normalizeNumbers(a.Params).(map[string]any)
+	}
+	if b.Params != nil {
+		b.Params = normalizeNumbers(b.Params).(map[string]any)
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+// 复制value并将其中的数值统一为int64，无法无损表示为int64的数值统一为float64
+func normalizeNumbers(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		m := make(map[string]any, len(v))
+		for k, item := range v {
+			m[k] = normalizeNumbers(item)
+		}
+		return m
+	case []any:
+		list := make([]any, len(v))
+		for i, item := range v {
+			list[i] = normalizeNumbers(item)
+		}
+		return list
+	}
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return rv.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if u := rv.Uint(); u <= math.MaxInt64 {
+			return int64(u)
+		}
+		return float64(rv.Uint())
+	case reflect.Float32, reflect.Float64:
+		f := rv.Float()
+		if f == math.Trunc(f) && f >= math.MinInt64 && f < math.MaxInt64 {
+			return int64(f)
+		}
+		return f
+	}
+	return value
+}
+
+func sortedNames(names set[ComponentName]) (ret []ComponentName) {
+	for name := range names {
+		ret = append(ret, name)
+	}
+	slices.Sort(ret)
+	return
+}
+
+// 容器自身在容器树中的路径，根容器为空
+func containerPath(c IComponentContainer) (path []ComponentName) {
+	for current := c; current != nil && current.GetParent() != nil; current = current.GetParent() {
+		path = append(path, current.GetContext().Config.Name)
+	}
+	slices.Reverse(path)
+	return
+}
@@ -0,0 +1,215 @@
+package compcont
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type reloadInstance struct {
+	config    string
+	destroyed atomic.Bool
+}
+
+func newReloadRegistry() IFactoryRegistry {
+	r := NewFactoryRegistry()
+	MustRegister(r, &TypedSimpleComponentFactory[string, *reloadInstance]{
+		TypeID: "reload",
+		CreateInstanceFunc: func(ctx Context, config string) (instance *reloadInstance, err error) {
+			if config == "fail" {
+				err = errors.New("create failed")
+				return
+			}
+			instance = &reloadInstance{config: config}
+			return
+		},
+		DestroyInstanceFunc: func(ctx Context, instance *reloadInstance) (err error) {
+			instance.destroyed.Store(true)
+			return
+		},
+	})
+	return r
+}
+
+func TestReloadNamedComponents(t *testing.T) {
+	cc := NewComponentContainer(WithFactoryRegistry(newReloadRegistry()))
+	configs := []ComponentConfig{
+		{Name: "a", Type: "reload", Config: "a1"},
+		{Name: "b", Type: "reload", Deps: []ComponentName{"a"}, Config: "b1"},
+		{Name: "c", Type: "reload", Config: "c1"},
+		{Name: "d", Type: "reload", Config: "d1"},
+	}
+	assert.NoError(t, cc.LoadNamedComponents(configs))
+
+	get := func(name ComponentName) *reloadInstance {
+		component, err := GetComponent[*reloadInstance](cc, name)
+		assert.NoError(t, err)
+		return component.Instance
+	}
+	a1, b1, c1, d1 := get("a"), get("b"), get("c"), get("d")
+
+	// a变化，依赖a的b一同重建，c不受影响，d被删除，e被新增
+	err := cc.(IReloadableContainer).ReloadNamedComponents([]ComponentConfig{
+		{Name: "a", Type: "reload", Config: "a2"},
+		{Name: "b", Type: "reload", Deps: []ComponentName{"a"}, Config: "b1"},
+		{Name: "c", Type: "reload", Config: "c1"},
+		{Name: "e", Type: "reload", Deps: []ComponentName{"a"}, Config: "e1"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "a2", get("a").config)
+	assert.NotSame(t, b1, get("b"))
+	assert.Same(t, c1, get("c"))
+	assert.Equal(t, "e1", get("e").config)
+	assert.ElementsMatch(t, []ComponentName{"a", "b", "c", "e"}, cc.LoadedComponentNames())
+	assert.True(t, a1.destroyed.Load())
+	assert.True(t, b1.destroyed.Load())
+	assert.False(t, c1.destroyed.Load())
+	assert.True(t, d1.destroyed.Load())
+
+	// 构建失败时回滚，旧组件保持可用
+	a2, b2 := get("a"), get("b")
+	err = cc.(IReloadableContainer).ReloadNamedComponents([]ComponentConfig{
+		{Name: "a", Type: "reload", Config: "a3"},
+		{Name: "b", Type: "reload", Deps: []ComponentName{"a"}, Config: "fail"},
+		{Name: "c", Type: "reload", Config: "c1"},
+	})
+	assert.Error(t, err)
+	assert.Same(t, a2, get("a"))
+	assert.Same(t, b2, get("b"))
+	assert.False(t, a2.destroyed.Load())
+	assert.ElementsMatch(t, []ComponentName{"a", "b", "c", "e"}, cc.LoadedComponentNames())
+
+	// 回滚后依赖关系同样恢复
+	err = cc.UnloadNamedComponents([]ComponentName{"a"}, false)
+	assert.ErrorIs(t, err, ErrComponentHasDependents)
+}
+
+func TestReloadHandle(t *testing.T) {
+	cc := NewComponentContainer(WithFactoryRegistry(newReloadRegistry()), WithHandleGracePeriod(time.Minute))
+	assert.NoError(t, cc.LoadNamedComponents([]ComponentConfig{{Name: "a", Type: "reload", Config: "a1"}}))
+
+	h, err := GetHandle[*reloadInstance](cc, "a")
+	assert.NoError(t, err)
+	h2, err := GetHandle[*reloadInstance](cc, "a")
+	assert.NoError(t, err)
+	assert.Same(t, h, h2)
+
+	a1, release := h.Acquire()
+	assert.Equal(t, "a1", a1.config)
+
+	assert.NoError(t, cc.(IReloadableContainer).ReloadNamedComponents([]ComponentConfig{{Name: "a", Type: "reload", Config: "a2"}}))
+	assert.Equal(t, "a2", h.Get().config)
+
+	// 旧实例仍在使用中，release之后才会被销毁
+	assert.False(t, a1.destroyed.Load())
+	release()
+	assert.Eventually(t, func() bool { return a1.destroyed.Load() }, time.Second, time.Millisecond)
+
+	// 实例类型与句柄类型不匹配
+	_, err = GetHandle[IComponentA](cc, "a")
+	assert.ErrorIs(t, err, ErrComponentTypeMismatch)
+}
+
+type wrappedContainer struct {
+	IComponentContainer
+}
+
+func (c wrappedContainer) Unwrap() IComponentContainer {
+	return c.IComponentContainer
+}
+
+func TestHandleRemoved(t *testing.T) {
+	cc := NewComponentContainer(WithFactoryRegistry(newReloadRegistry()))
+	assert.NoError(t, cc.LoadNamedComponents([]ComponentConfig{
+		{Name: "a", Type: "reload", Config: "a1"},
+		{Name: "b", Type: "reload", Config: "b1"},
+	}))
+
+	// 包装了容器的组件实例同样可以获取句柄
+	ha, err := GetHandle[*reloadInstance](wrappedContainer{cc}, "a")
+	assert.NoError(t, err)
+	hb, err := GetHandle[*reloadInstance](cc, "b")
+	assert.NoError(t, err)
+	assert.NoError(t, ha.Err())
+
+	// 卸载后句柄不再返回已销毁的实例
+	assert.NoError(t, cc.UnloadNamedComponents([]ComponentName{"a"}, false))
+	assert.Nil(t, ha.Get())
+	assert.ErrorIs(t, ha.Err(), ErrComponentNameNotFound)
+
+	// 热重载中被删除的组件同样如此
+	assert.NoError(t, cc.(IReloadableContainer).ReloadNamedComponents(nil))
+	instance, release := hb.Acquire()
+	release()
+	assert.Nil(t, instance)
+	assert.ErrorIs(t, hb.Err(), ErrComponentNameNotFound)
+
+	// 重新加载后获取的是新句柄
+	assert.NoError(t, cc.LoadNamedComponents([]ComponentConfig{{Name: "a", Type: "reload", Config: "a2"}}))
+	ha2, err := GetHandle[*reloadInstance](cc, "a")
+	assert.NoError(t, err)
+	assert.Equal(t, "a2", ha2.Get().config)
+}
+
+func TestRebuildComponents(t *testing.T) {
+	cc := NewComponentContainer(WithFactoryRegistry(newReloadRegistry()))
+	assert.NoError(t, cc.LoadNamedComponents([]ComponentConfig{
+		{Name: "a", Type: "reload", Config: "a1"},
+		{Name: "b", Type: "reload", Deps: []ComponentName{"a"}, Config: "b1"},
+		{Name: "c", Type: "reload", Config: "c1"},
+	}))
+	get := func(name ComponentName) *reloadInstance {
+		component, err := GetComponent[*reloadInstance](cc, name)
+		assert.NoError(t, err)
+		return component.Instance
+	}
+	a1, b1, c1 := get("a"), get("b"), get("c")
+
+	// 配置不变，a及依赖a的b被重建
+	assert.NoError(t, cc.(IRebuildableContainer).RebuildComponents([]ComponentName{"a"}))
+	assert.NotSame(t, a1, get("a"))
+	assert.NotSame(t, b1, get("b"))
+	assert.Same(t, c1, get("c"))
+	assert.True(t, a1.destroyed.Load())
+
+	assert.ErrorIs(t, cc.(IRebuildableContainer).RebuildComponents([]ComponentName{"x"}), ErrComponentNameNotFound)
+
+	graph := cc.(ISnapshotContainer).Snapshot().Graph()
+	assert.Len(t, graph.Nodes, 3)
+	assert.Equal(t, []GraphEdge{{From: "/b", To: "/a"}}, graph.Edges)
+	assert.Contains(t, graph.DOT(), `"/b" -> "/a";`)
+}
+
+func TestReloadNumericConfig(t *testing.T) {
+	r := NewFactoryRegistry()
+	MustRegister(r, &TypedSimpleComponentFactory[map[string]any, *map[string]any]{
+		TypeID: "map",
+		CreateInstanceFunc: func(ctx Context, config map[string]any) (instance *map[string]any, err error) {
+			return &config, nil
+		},
+	})
+	cc := NewComponentContainer(WithFactoryRegistry(r))
+	assert.NoError(t, cc.LoadNamedComponents([]ComponentConfig{
+		{Name: "a", Type: "map", Config: map[string]any{"port": 6379, "ratio": 0.5, "hosts": []any{"a", 1}}},
+	}))
+	a1, err := GetComponent[*map[string]any](cc, "a")
+	assert.NoError(t, err)
+
+	// JSON解码得到的float64与YAML解码得到的int值相同时不视为变化
+	assert.NoError(t, cc.(IReloadableContainer).ReloadNamedComponents([]ComponentConfig{
+		{Name: "a", Type: "map", Config: map[string]any{"port": float64(6379), "ratio": 0.5, "hosts": []any{"a", float64(1)}}},
+	}))
+	a2, err := GetComponent[*map[string]any](cc, "a")
+	assert.NoError(t, err)
+	assert.Same(t, a1.Instance, a2.Instance)
+
+	assert.NoError(t, cc.(IReloadableContainer).ReloadNamedComponents([]ComponentConfig{
+		{Name: "a", Type: "map", Config: map[string]any{"port": 6380.5, "ratio": 0.5, "hosts": []any{"a", 1}}},
+	}))
+	a3, err := GetComponent[*map[string]any](cc, "a")
+	assert.NoError(t, err)
+	assert.NotSame(t, a1.Instance, a3.Instance)
+}
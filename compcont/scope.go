@@ -0,0 +1,178 @@
+package compcont
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"sync"
+)
+
+type ComponentScope string
+
+const (
+	ScopeSingleton ComponentScope = "singleton" // 容器中只有一个实例，默认值
+	ScopeTransient ComponentScope = "transient" // 每次获取都创建新实例
+	ScopeScoped    ComponentScope = "scoped"    // 每个Scope中只有一个实例，Scope关闭时销毁
+)
+
+// 非单例组件放入容器的占位实例
+type scopedComponent struct{}
+
+func isScopedPlaceholder(component Component) bool {
+	_, ok := component.Instance.(*scopedComponent)
+	return ok
+}
+
+func (c *ComponentContainer) isSingleton(name ComponentName) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return !isScopedPlaceholder(c.components[name])
+}
+
+// Scope 一个由使用方创建的生命周期范围，例如一次HTTP请求
+//
+// scoped组件在同一个Scope中只创建一次，在Scope中创建的scoped和transient组件在Close时按创建的逆序销毁
+type Scope struct {
+	mu      sync.Mutex
+	entries map[componentKey]*scopeEntry
+	created []Component // 按创建顺序记录的实例
+	closed  bool
+}
+
+type scopeEntry struct {
+	mu        sync.Mutex
+	component Component
+	done      bool
+}
+
+func NewScope() *Scope {
+	return &Scope{entries: make(map[componentKey]*scopeEntry)}
+}
+
+// GetComponent 在Scope中获取容器中的一个具名组件，单例组件与直接从容器获取相同
+func (s *Scope) GetComponent(container IComponentContainer, name ComponentName) (component Component, err error) {
+	c, ok := unwrapContainer[*ComponentContainer](container)
+	if !ok {
+		return container.GetComponent(name)
+	}
+	return c.getComponent(name, s)
+}
+
+// Close 销毁Scope中创建的全部实例，之后不能再在该Scope中创建实例
+func (s *Scope) Close() (err error) {
+	s.mu.Lock()
+	created := s.created
+	s.created = nil
+	s.entries = nil
+	s.closed = true
+	s.mu.Unlock()
+
+	var errs []error
+	for _, component := range slices.Backward(created) {
+		c := component.Context.Container.(*ComponentContainer)
+		if err1 := c.destroyComponent(component.Context.Config.Name, component); err1 != nil {
+			errs = append(errs, fmt.Errorf("destroy component %s failed, %w", component.Context.Config.Name, err1))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (s *Scope) track(component Component) (err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return ErrScopeClosed
+	}
+	s.created = append(s.created, component)
+	return
+}
+
+// 获取scoped组件在Scope中的实例，不存在时创建
+func (s *Scope) getOrCreate(c *ComponentContainer, config ComponentConfig) (component Component, err error) {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		err = ErrScopeClosed
+		return
+	}
+	key := componentKey{container: c, name: config.Name}
+	entry, ok := s.entries[key]
+	if !ok {
+		entry = &scopeEntry{}
+		s.entries[key] = entry
+	}
+	s.mu.Unlock()
+
+	// 同一组件的并发获取只创建一次，创建失败时下次获取会重试
+	entry.mu.Lock()
+	defer entry.mu.Unlock()
+	if entry.done {
+		return entry.component, nil
+	}
+	component, err = c.createInScope(config, s)
+	if err != nil {
+		return
+	}
+	entry.component = component
+	entry.done = true
+	return
+}
+
+// 创建非单例组件的实例，在Scope中创建时由Scope负责销毁
+func (c *ComponentContainer) createInScope(config ComponentConfig, scope *Scope) (component Component, err error) {
+	component, err = c.loadComponent(config, scope, nil)
+	if err != nil || scope == nil {
+		return
+	}
+	if err = scope.track(component); err != nil {
+		if err1 := c.destroyComponent(config.Name, component); err1 != nil {
+			err = errors.Join(err, err1)
+		}
+		component = Component{}
+	}
+	return
+}
+
+func (c *ComponentContainer) getScopedComponent(config ComponentConfig, scope *Scope) (component Component, err error) {
+	switch config.Scope {
+	case ScopeTransient:
+		// 不在Scope中获取时，实例的生命周期由调用方负责，与匿名组件相同
+		return c.createInScope(config, scope)
+	default:
+		if scope == nil {
+			err = fmt.Errorf("%w, name: %s", ErrComponentScopeRequired, config.Name)
+			return
+		}
+		return scope.getOrCreate(c, config)
+	}
+}
+
+// GetScopedComponent 在Scope中获取一个具名组件并转换为指定的实例类型
+func GetScopedComponent[Instance any](scope *Scope, container IComponentContainer, name ComponentName) (ret TypedComponent[Instance], err error) {
+	component, err := scope.GetComponent(container, name)
+	if err != nil {
+		return
+	}
+	instance, ok := component.Instance.(Instance)
+	if !ok {
+		err = fmt.Errorf("get scoped component failed, %w, name: %s, component type: %s, expected instance type %v, but got %T", ErrComponentTypeMismatch, name, component.Context.Config.Type, reflect.TypeFor[Instance](), component.Instance)
+		return
+	}
+	ret = TypedComponent[Instance]{Context: component.Context, Instance: instance}
+	return
+}
+
+type ctxKeyScope struct{}
+
+// ContextWithScope 将Scope放入context，便于在一次请求的处理链路中传递
+func ContextWithScope(ctx context.Context, scope *Scope) context.Context {
+	return context.WithValue(ctx, ctxKeyScope{}, scope)
+}
+
+// ScopeFromContext 从context中取出Scope，不存在时返回nil
+func ScopeFromContext(ctx context.Context) *Scope {
+	scope, _ := ctx.Value(ctxKeyScope{}).(*Scope)
+	return scope
+}
@@ -0,0 +1,90 @@
+package compcont
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScope(t *testing.T) {
+	r := newReloadRegistry()
+	MustRegister(r, &TypedSimpleComponentFactory[ConfigC, *reloadInstance]{
+		TypeID: "with_ref",
+		CreateInstanceFunc: func(ctx Context, config ConfigC) (instance *reloadInstance, err error) {
+			dep, err := Ref[*reloadInstance]{Path: config.A.Path}.Load(ctx)
+			if err != nil {
+				return
+			}
+			return &reloadInstance{config: dep.Instance.config}, nil
+		},
+	})
+	cc := NewComponentContainer(WithFactoryRegistry(r))
+	assert.NoError(t, cc.LoadNamedComponents([]ComponentConfig{
+		{Name: "single", Type: "reload", Config: "single"},
+		{Name: "tx", Type: "reload", Config: "tx", Scope: ScopeScoped},
+		{Name: "client", Type: "reload", Config: "client", Scope: ScopeTransient},
+		{Name: "handler", Type: "with_ref", Config: map[string]any{"a": "tx"}, Scope: ScopeScoped},
+	}))
+
+	// scoped组件必须在scope中获取，不参与类型查找
+	_, err := cc.GetComponent("tx")
+	assert.ErrorIs(t, err, ErrComponentScopeRequired)
+	found, err := FindComponentsByType[*reloadInstance](cc)
+	assert.NoError(t, err)
+	assert.Len(t, found, 1)
+
+	// transient组件每次获取都是新实例
+	c1, err := GetComponent[*reloadInstance](cc, "client")
+	assert.NoError(t, err)
+	c2, err := GetComponent[*reloadInstance](cc, "client")
+	assert.NoError(t, err)
+	assert.NotSame(t, c1.Instance, c2.Instance)
+
+	scope1, scope2 := NewScope(), NewScope()
+	tx1, err := GetScopedComponent[*reloadInstance](scope1, cc, "tx")
+	assert.NoError(t, err)
+	tx1Again, err := GetScopedComponent[*reloadInstance](scope1, cc, "tx")
+	assert.NoError(t, err)
+	assert.Same(t, tx1.Instance, tx1Again.Instance)
+	tx2, err := GetScopedComponent[*reloadInstance](scope2, cc, "tx")
+	assert.NoError(t, err)
+	assert.NotSame(t, tx1.Instance, tx2.Instance)
+
+	// scoped组件的依赖在同一个scope中获取
+	_, err = GetScopedComponent[*reloadInstance](scope1, cc, "handler")
+	assert.NoError(t, err)
+	single, err := GetScopedComponent[*reloadInstance](scope1, cc, "single")
+	assert.NoError(t, err)
+	client, err := GetScopedComponent[*reloadInstance](scope1, cc, "client")
+	assert.NoError(t, err)
+
+	assert.NoError(t, scope1.Close())
+	assert.True(t, tx1.Instance.destroyed.Load())
+	assert.True(t, client.Instance.destroyed.Load())
+	assert.False(t, single.Instance.destroyed.Load())
+	assert.False(t, tx2.Instance.destroyed.Load())
+	_, err = scope1.GetComponent(cc, "tx")
+	assert.ErrorIs(t, err, ErrScopeClosed)
+	assert.NoError(t, scope2.Close())
+	assert.True(t, tx2.Instance.destroyed.Load())
+
+	// 声明的依赖只检查是否存在，不会为transient依赖创建额外的实例
+	var created int
+	MustRegister(r, &TypedSimpleComponentFactory[string, *reloadInstance]{
+		TypeID: "counted",
+		CreateInstanceFunc: func(ctx Context, config string) (instance *reloadInstance, err error) {
+			created++
+			return &reloadInstance{config: config}, nil
+		},
+	})
+	assert.NoError(t, cc.LoadNamedComponents([]ComponentConfig{
+		{Name: "counted", Type: "counted", Config: "counted", Scope: ScopeTransient},
+		{Name: "user", Type: "reload", Config: "user", Deps: []ComponentName{"counted"}, Scope: ScopeScoped},
+		{Name: "eager", Type: "reload", Config: "eager", Deps: []ComponentName{"counted"}},
+	}))
+	scope3 := NewScope()
+	_, err = GetScopedComponent[*reloadInstance](scope3, cc, "user")
+	assert.NoError(t, err)
+	assert.NoError(t, scope3.Close())
+	assert.Equal(t, 0, created)
+}
@@ -0,0 +1,153 @@
+package compcont
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+type selectorOp string
+
+const (
+	selectorEquals    selectorOp = "="
+	selectorNotEquals selectorOp = "!="
+	selectorIn        selectorOp = "in"
+	selectorNotIn     selectorOp = "notin"
+	selectorExists    selectorOp = "exists"
+	selectorNotExists selectorOp = "!"
+)
+
+type selectorRequirement struct {
+	key    string
+	op     selectorOp
+	values []string
+}
+
+func (r selectorRequirement) matches(labels map[string]string) bool {
+	value, ok := labels[r.key]
+	switch r.op {
+	case selectorEquals:
+		return ok && value == r.values[0]
+	case selectorNotEquals:
+		return !ok || value != r.values[0]
+	case selectorIn:
+		return ok && slices.Contains(r.values, value)
+	case selectorNotIn:
+		return !ok || !slices.Contains(r.values, value)
+	case selectorExists:
+		return ok
+	default:
+		return !ok
+	}
+}
+
+// Selector 组件标签的选择器，多个条件之间为与的关系，空的选择器匹配所有组件
+type Selector struct {
+	requirements []selectorRequirement
+	raw          string
+}
+
+// ParseSelector 解析以逗号分隔的标签选择条件，支持 key=value、key==value、key!=value、key in (a,b)、key notin (a,b)、key 和 !key，
+// 例如 role=middleware, server=public
+func ParseSelector(s string) (selector Selector, err error) {
+	selector.raw = s
+	for _, part := range splitSelector(s) {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		var requirement selectorRequirement
+		requirement, err = parseRequirement(part)
+		if err != nil {
+			err = fmt.Errorf("%w, invalid selector %q, %w", ErrComponentConfigInvalid, s, err)
+			return
+		}
+		selector.requirements = append(selector.requirements, requirement)
+	}
+	return
+}
+
+// MustParseSelector 同 ParseSelector，出错时panic
+func MustParseSelector(s string) Selector {
+	selector, err := ParseSelector(s)
+	if err != nil {
+		panic(err)
+	}
+	return selector
+}
+
+// 按逗号切分条件，括号中的逗号不切分
+func splitSelector(s string) (parts []string) {
+	depth, start := 0, 0
+	for i, ch := range s {
+		switch ch {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				parts = append(parts, s[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(parts, s[start:])
+}
+
+func parseRequirement(s string) (r selectorRequirement, err error) {
+	if key, ok := strings.CutPrefix(s, "!"); ok {
+		r = selectorRequirement{key: strings.TrimSpace(key), op: selectorNotExists}
+	} else if key, value, ok := strings.Cut(s, "!="); ok {
+		r = selectorRequirement{key: strings.TrimSpace(key), op: selectorNotEquals, values: []string{strings.TrimSpace(value)}}
+	} else if key, value, ok := strings.Cut(s, "=="); ok {
+		r = selectorRequirement{key: strings.TrimSpace(key), op: selectorEquals, values: []string{strings.TrimSpace(value)}}
+	} else if key, value, ok := strings.Cut(s, "="); ok {
+		r = selectorRequirement{key: strings.TrimSpace(key), op: selectorEquals, values: []string{strings.TrimSpace(value)}}
+	} else if fields := strings.Fields(s); len(fields) == 1 {
+		r = selectorRequirement{key: fields[0], op: selectorExists}
+	} else if len(fields) >= 2 && (fields[1] == string(selectorIn) || fields[1] == string(selectorNotIn)) {
+		r = selectorRequirement{key: fields[0], op: selectorOp(fields[1])}
+		list := strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(s[len(fields[0]):]), fields[1]))
+		if !strings.HasPrefix(list, "(") || !strings.HasSuffix(list, ")") {
+			err = fmt.Errorf("values of %s should be enclosed in parentheses", fields[1])
+			return
+		}
+		for _, value := range strings.Split(list[1:len(list)-1], ",") {
+			if value = strings.TrimSpace(value); value != "" {
+				r.values = append(r.values, value)
+			}
+		}
+	} else {
+		err = fmt.Errorf("unknown requirement %q", s)
+		return
+	}
+	if r.key == "" || strings.ContainsAny(r.key, " =!()") {
+		err = fmt.Errorf("invalid label key in requirement %q", s)
+	}
+	return
+}
+
+// Matches 判断标签是否满足选择器的全部条件
+func (s Selector) Matches(labels map[string]string) bool {
+	for _, requirement := range s.requirements {
+		if !requirement.matches(labels) {
+			return false
+		}
+	}
+	return true
+}
+
+func (s Selector) Empty() bool {
+	return len(s.requirements) == 0
+}
+
+func (s Selector) String() string {
+	return s.raw
+}
+
+// UnmarshalText 使选择器可以直接作为配置字段使用
+func (s *Selector) UnmarshalText(text []byte) (err error) {
+	*s, err = ParseSelector(string(text))
+	return
+}
@@ -0,0 +1,59 @@
+package compcont
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSelector(t *testing.T) {
+	labels := map[string]string{"role": "middleware", "server": "public"}
+	for s, expected := range map[string]bool{
+		"":                                true,
+		"role=middleware, server=public":  true,
+		"role==middleware,server!=public": false,
+		"role in (middleware, handler)":   true,
+		"server notin (public)":           false,
+		"role, !internal":                 true,
+		"!role":                           false,
+	} {
+		selector, err := ParseSelector(s)
+		assert.NoError(t, err, s)
+		assert.Equal(t, expected, selector.Matches(labels), s)
+	}
+	_, err := ParseSelector("role in middleware")
+	assert.ErrorIs(t, err, ErrComponentConfigInvalid)
+
+	r := NewFactoryRegistry()
+	MustRegister(r, &TypedSimpleComponentFactory[string, string]{
+		TypeID: "echo",
+		CreateInstanceFunc: func(ctx Context, config string) (instance string, err error) {
+			return config, nil
+		},
+	}, &TypedSimpleComponentFactory[[]ComponentConfig, IComponentContainer]{
+		TypeID: "inline",
+		CreateInstanceFunc: func(ctx Context, config []ComponentConfig) (instance IComponentContainer, err error) {
+			instance = NewComponentContainer(WithParentContainer(ctx.Container), WithContext(ctx), WithFactoryRegistry(r))
+			err = instance.LoadNamedComponents(config)
+			return
+		},
+	})
+	cc := NewComponentContainer(WithFactoryRegistry(r))
+	assert.NoError(t, cc.LoadNamedComponents([]ComponentConfig{
+		{Name: "a", Type: "echo", Config: "a", Labels: map[string]string{"role": "middleware", "server": "public"}},
+		{Name: "b", Type: "echo", Config: "b", Labels: map[string]string{"role": "middleware", "server": "admin"}},
+		{Name: "c", Type: "echo", Config: "c"},
+		{Name: "child", Type: "inline", Config: []ComponentConfig{
+			{Name: "d", Type: "echo", Config: "d", Labels: map[string]string{"role": "middleware", "server": "public"}},
+			{Name: "e", Refer: "../c", Labels: map[string]string{"role": "middleware", "server": "public"}},
+		}},
+	}))
+
+	components, err := FindComponentsByType[string](cc, WithSelector(MustParseSelector("role=middleware, server=public")), FindInDescendants())
+	assert.NoError(t, err)
+	var instances []string
+	for _, component := range components {
+		instances = append(instances, component.Instance)
+	}
+	assert.Equal(t, []string{"a", "d", "c"}, instances)
+}
@@ -52,7 +52,7 @@ type CreateInstanceFunc func(ctx Context, config any) (instance any, err error)
 
 type DestroyInstanceFunc func(ctx Context, instance any) (err error)
 
-func decodeMapConfig[Config any](mapConfig map[string]any, structureConfig *Config) (err error) {
+func decodeMapConfig(mapConfig map[string]any, structureConfig any) (err error) {
 	decoder, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
 		TagName:     ConfigFieldTagName,
 		ErrorUnused: true,            // 配置文件如果多余出未使用的字段，则报错
@@ -61,6 +61,8 @@ func decodeMapConfig[Config any](mapConfig map[string]any, structureConfig *Conf
 		DecodeHook: mapstructure.ComposeDecodeHookFunc(
 			mapstructure.StringToTimeDurationHookFunc(),     // 自动解析duration
 			mapstructure.StringToTimeHookFunc(time.RFC3339), // 自动解析时间
+			legacyRefHookFunc,                       // 兼容 { refer: path } 形式的Ref
+			mapstructure.TextUnmarshallerHookFunc(), // 支持Ref等实现了encoding.TextUnmarshaler的类型
 		),
 	})
 	if err != nil {
@@ -73,27 +75,47 @@ func decodeMapConfig[Config any](mapConfig map[string]any, structureConfig *Conf
 	return
 }
 
+// 字段由 TypedComponentConfig 改为 Ref 后，旧配置中只有refer的 { refer: path } 仍然可以解码为Ref
+func legacyRefHookFunc(from reflect.Type, to reflect.Type, data any) (any, error) {
+	if from.Kind() != reflect.Map || !to.Implements(referPatherType) {
+		return data, nil
+	}
+	m, ok := data.(map[string]any)
+	if !ok || len(m) != 1 {
+		return data, nil
+	}
+	if path, ok := m["refer"].(string); ok {
+		return path, nil
+	}
+	return data, nil
+}
+
 type TypedCreateInstanceFunc[Config any, Instance any] func(ctx Context, config Config) (instance Instance, err error)
 
 func (f TypedCreateInstanceFunc[Config, Instance]) ToAny() CreateInstanceFunc {
 	return func(ctx Context, rawConfig any) (comp any, err error) {
-		switch v := rawConfig.(type) {
-		case nil:
-			var cfg Config
-			return f(ctx, cfg)
-		case Config:
-			return f(ctx, v)
-		case map[string]any:
-			var cfg Config
-			err = decodeMapConfig(v, &cfg)
-			if err != nil {
-				return
-			}
-			return f(ctx, cfg)
-		default:
-			err = fmt.Errorf("unexpected config type %s", reflect.ValueOf(rawConfig))
+		cfg, err := decodeTypedConfig[Config](rawConfig)
+		if err != nil {
 			return
 		}
+		return f(ctx, cfg)
+	}
+}
+
+// 将原始配置解码为具体的配置类型
+func decodeTypedConfig[Config any](rawConfig any) (cfg Config, err error) {
+	switch v := rawConfig.(type) {
+	case nil:
+		return
+	case Config:
+		cfg = v
+		return
+	case map[string]any:
+		err = decodeMapConfig(v, &cfg)
+		return
+	default:
+		err = fmt.Errorf("unexpected config type %s", reflect.ValueOf(rawConfig))
+		return
 	}
 }
 
@@ -109,21 +131,37 @@ func (f TypedDestroyInstanceFunc[Component]) ToAny() DestroyInstanceFunc {
 	}
 }
 
+type TypedStartInstanceFunc[Instance any] func(ctx Context, instance Instance) (err error)
+
+func (f TypedStartInstanceFunc[Component]) ToAny() func(ctx Context, instance any) (err error) {
+	return func(ctx Context, component any) (err error) {
+		if v, ok := component.(Component); ok {
+			return f(ctx, v)
+		}
+		err = fmt.Errorf("unexpected component type %s", reflect.ValueOf(component))
+		return
+	}
+}
+
 type TypedComponentConfig[Config any, Component any] struct {
-	Name   ComponentName   `json:"name" yaml:"name"`
-	Type   ComponentTypeID `json:"type" yaml:"type"`     // 组件类型
-	Refer  string          `json:"refer" yaml:"refer"`   // 来自其他组件的引用
-	Deps   []ComponentName `json:"deps" yaml:"deps"`     // 构造该组件需要依赖的其他组件名称
-	Config Config          `json:"config" yaml:"config"` // 组件的自身配置
+	Name    ComponentName   `json:"name" yaml:"name"`
+	Type    ComponentTypeID `json:"type" yaml:"type"`       // 组件类型
+	Refer   string          `json:"refer" yaml:"refer"`     // 来自其他组件的引用
+	Deps    []ComponentName `json:"deps" yaml:"deps"`       // 构造该组件需要依赖的其他组件名称
+	Config  Config          `json:"config" yaml:"config"`   // 组件的自身配置
+	Enabled *bool           `json:"enabled" yaml:"enabled"` // 为false时加载会返回 ErrComponentDisabled
+	When    string          `json:"when" yaml:"when"`       // 求值为false时加载会返回 ErrComponentDisabled
 }
 
 func (c TypedComponentConfig[Config, Component]) ToAny() ComponentConfig {
 	return ComponentConfig{
-		Name:   c.Name,
-		Type:   c.Type,
-		Refer:  c.Refer,
-		Deps:   c.Deps,
-		Config: c.Config,
+		Name:    c.Name,
+		Type:    c.Type,
+		Refer:   c.Refer,
+		Deps:    c.Deps,
+		Config:  c.Config,
+		Enabled: c.Enabled,
+		When:    c.When,
 	}
 }
 
@@ -134,13 +172,19 @@ func (c TypedComponentConfig[Config, Component]) LoadComponent(container ICompon
 type TypedSimpleComponentFactory[Config any, Component any] struct {
 	TypeID              ComponentTypeID
 	CreateInstanceFunc  TypedCreateInstanceFunc[Config, Component]
+	StartInstanceFunc   TypedStartInstanceFunc[Component] // 可选，实例创建后启动组件，例如建立连接或预热
 	DestroyInstanceFunc TypedDestroyInstanceFunc[Component]
+	Version             string // 可选，组件类型的版本
 }
 
 func (s *TypedSimpleComponentFactory[Config, Component]) Type() ComponentTypeID {
 	return s.TypeID
 }
 
+func (s *TypedSimpleComponentFactory[Config, Component]) TypeVersion() string {
+	return s.Version
+}
+
 func (s *TypedSimpleComponentFactory[Config, Component]) CreateInstance(ctx Context, config any) (instance any, err error) {
 	if s.CreateInstanceFunc == nil {
 		return
@@ -148,6 +192,19 @@ func (s *TypedSimpleComponentFactory[Config, Component]) CreateInstance(ctx Cont
 	return s.CreateInstanceFunc.ToAny()(ctx, config)
 }
 
+// DecodeConfig implements IComponentConfigDecoder.
+func (s *TypedSimpleComponentFactory[Config, Component]) DecodeConfig(rawConfig any) (config any, err error) {
+	return decodeTypedConfig[Config](rawConfig)
+}
+
+// StartInstance implements IComponentStarter.
+func (s *TypedSimpleComponentFactory[Config, Component]) StartInstance(ctx Context, instance any) (err error) {
+	if s.StartInstanceFunc == nil {
+		return
+	}
+	return s.StartInstanceFunc.ToAny()(ctx, instance)
+}
+
 func (s *TypedSimpleComponentFactory[Config, Component]) DestroyInstance(ctx Context, instance any) (err error) {
 	if s.DestroyInstanceFunc == nil {
 		return
@@ -0,0 +1,81 @@
+package compcont
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+)
+
+// IComponentTemplate 组件模板，具名或匿名组件通过template字段引用实现了该接口的组件，以params实例化出组件配置
+//
+// 实例化的结果提供type、refer、deps、config、lazy、scope，引用模板的组件自身的name、enabled、when始终生效，
+// 自身设置的deps追加在模板的deps之后，设置了lazy或scope时覆盖模板的值，config为map时逐个顶层字段覆盖模板的config
+type IComponentTemplate interface {
+	Instantiate(params map[string]any) (config ComponentConfig, err error)
+}
+
+// 将引用了模板的组件配置展开为完整的组件配置，同时返回被引用的模板组件
+func (c *ComponentContainer) expandTemplate(config ComponentConfig) (expanded ComponentConfig, template Component, err error) {
+	if config.Template == "" {
+		if config.Params != nil {
+			err = fmt.Errorf("%w, component %s has params but no template", ErrComponentConfigInvalid, config.Name)
+			return
+		}
+		expanded = config
+		return
+	}
+	if config.Type != "" || config.Refer != "" {
+		err = fmt.Errorf("%w, component %s can not set type or refer together with template", ErrComponentConfigInvalid, config.Name)
+		return
+	}
+	template, err = resolveRefer(c, config.Template)
+	if err != nil {
+		err = fmt.Errorf("%w, template %s of component %s not found, %w", ErrComponentDependencyNotFound, config.Template, config.Name, err)
+		return
+	}
+	templatePath := formatPath(append(containerPath(template.Context.Container), template.Context.Config.Name))
+	instance, ok := template.Instance.(IComponentTemplate)
+	if !ok {
+		err = fmt.Errorf("%w, component %s referred by template of component %s is not a template, got %T", ErrComponentTypeMismatch, templatePath, config.Name, template.Instance)
+		return
+	}
+	expanded, err = instance.Instantiate(config.Params)
+	if err != nil {
+		err = fmt.Errorf("%w, instantiate template %s for component %s failed, %w", ErrComponentConfigInvalid, templatePath, config.Name, err)
+		return
+	}
+	if expanded.Type == "" && expanded.Refer == "" {
+		err = fmt.Errorf("%w, template %s instantiated for component %s has neither type nor refer", ErrComponentConfigInvalid, templatePath, config.Name)
+		return
+	}
+
+	expanded.Name = config.Name
+	expanded.Template = ""
+	expanded.Params = nil
+	expanded.Enabled = config.Enabled
+	expanded.When = config.When
+	expanded.Labels = config.Labels
+	expanded.Annotations = config.Annotations
+	expanded.Deps = append(slices.Clone(expanded.Deps), config.Deps...)
+	if config.Lazy {
+		expanded.Lazy = true
+	}
+	if config.Scope != "" {
+		expanded.Scope = config.Scope
+	}
+	if config.Config != nil {
+		base, baseOk := expanded.Config.(map[string]any)
+		patch, patchOk := config.Config.(map[string]any)
+		if !patchOk || (!baseOk && expanded.Config != nil) {
+			err = fmt.Errorf("%w, config of component %s can only patch a map config of template %s", ErrComponentConfigInvalid, config.Name, templatePath)
+			return
+		}
+		merged := maps.Clone(base)
+		if merged == nil {
+			merged = make(map[string]any, len(patch))
+		}
+		maps.Copy(merged, patch)
+		expanded.Config = merged
+	}
+	return
+}
@@ -2,7 +2,9 @@ package compcont
 
 import (
 	"fmt"
+	"log/slog"
 	"slices"
+	"strings"
 )
 
 type set[T comparable] map[T]struct{}
@@ -59,8 +61,9 @@ func topologicalSort(cfgMap map[ComponentName]set[ComponentName]) ([]ComponentNa
 	return result, nil
 }
 
-// 从当前节点定位一个组件的上下文
+// 从当前节点定位一个组件的上下文，进入其他容器时只能访问其导出的组件
 func find(currentNode IComponentContainer, findPath []ComponentName, absolute bool) (ctx Context, err error) {
+	origin := currentNode
 	// 如果是绝对路径，将currentNode指针指向容器树的根节点
 	if absolute {
 		for {
@@ -74,31 +77,124 @@ func find(currentNode IComponentContainer, findPath []ComponentName, absolute bo
 
 	var component Component
 	for i, partName := range findPath {
+		if name, ok := strings.CutPrefix(string(partName), AncestorReferPrefix); ok && i == 0 {
+			var result LookupResult
+			result, err = LookupInAncestors(currentNode, ComponentName(name))
+			if err != nil {
+				return
+			}
+			if result.Depth > 0 {
+				if c, ok := currentNode.(*ComponentContainer); ok {
+					c.logger.Debug("component resolved in ancestor container",
+						slog.String("name", name),
+						slog.String("from", formatContainerPath(containerPath(currentNode))),
+						slog.String("resolved", formatPath(result.Path)),
+						slog.Any("shadowed", result.shadowedPaths()),
+					)
+				}
+			}
+			currentNode, partName = result.container, ComponentName(name)
+		}
 		if partName == "." {
 			continue
 		}
 		if partName == ".." {
 			currentNode = currentNode.GetParent()
+			if currentNode == nil {
+				err = fmt.Errorf("refer path error, %w, root container has no parent", ErrComponentNameNotFound)
+				return
+			}
 			continue
 		}
-		component, err = currentNode.GetComponent(partName)
-		if err != nil {
+		if err = checkExported(origin, currentNode, partName); err != nil {
 			return
 		}
-
-		// 已经找到最后一个路径了，可以返回
+		// 已经找到最后一个路径了，返回其所在容器和名称，由调用方获取组件
 		if i == len(findPath)-1 {
-			ctx = component.Context
+			ctx = Context{Container: currentNode, Config: ComponentConfig{Name: partName}}
+			return
+		}
+
+		component, err = currentNode.GetComponent(partName)
+		if err != nil {
 			return
 		}
 
 		// 还要继续向后寻找，如果下一个要寻找的节点不是容器，则直接报错
-		if _, ok := component.Instance.(IComponentContainer); !ok {
+		container, ok := component.Instance.(IComponentContainer)
+		if !ok {
 			err = fmt.Errorf("refer path error, %s is not a container", partName)
 			return
 		}
+		currentNode = container
 	}
 
+	if component.Context.Container == nil {
+		err = fmt.Errorf("refer path error, %w, path does not end with a component name", ErrComponentNameNotFound)
+		return
+	}
 	ctx = component.Context
 	return
 }
+
+// 解析引用路径，以/开头的为绝对路径，以^开头时第一级名称沿祖先容器查找，见 LookupInAncestors
+func parseReferPath(refer string) (findPath []ComponentName, absolute bool, err error) {
+	parts := strings.Split(refer, "/")
+	if parts[0] == "" { // 绝对路径
+		absolute = true
+		parts = parts[1:]
+	}
+
+	for i, p := range parts {
+		name := p
+		if i == 0 && !absolute {
+			name = strings.TrimPrefix(p, AncestorReferPrefix)
+		}
+		if n := ComponentName(name); p != "." && p != ".." && !n.Validate() {
+			err = fmt.Errorf("%w, in refer %s", ErrComponentNameInvalid, refer)
+			return
+		} else {
+			findPath = append(findPath, ComponentName(p))
+		}
+	}
+	return
+}
+
+// 根据引用路径找到组件所在的容器和名称并检查组件是否存在，不获取组件实例
+func findReferTarget(currentNode IComponentContainer, refer string) (ctx Context, err error) {
+	findPath, absolute, err := parseReferPath(refer)
+	if err != nil {
+		return
+	}
+	ctx, err = find(currentNode, findPath, absolute)
+	if err != nil {
+		return
+	}
+	if !slices.Contains(ctx.Container.LoadedComponentNames(), ctx.Config.Name) {
+		err = fmt.Errorf("%w, name: %s", ErrComponentNameNotFound, ctx.Config.Name)
+	}
+	return
+}
+
+// 从当前节点出发，根据引用路径获取一个组件
+func resolveRefer(currentNode IComponentContainer, refer string) (component Component, err error) {
+	return resolveReferInScope(currentNode, refer, nil)
+}
+
+// 根据引用路径获取一个组件，scoped和transient组件在scope中获取
+func resolveReferInScope(currentNode IComponentContainer, refer string, scope *Scope) (component Component, err error) {
+	findPath, absolute, err := parseReferPath(refer)
+	if err != nil {
+		return
+	}
+
+	// 寻找到要引用的树节点，再从对应节点上获取组件
+	ctx, err := find(currentNode, findPath, absolute)
+	if err != nil {
+		return
+	}
+	if c, ok := ctx.Container.(*ComponentContainer); ok {
+		return c.getComponent(ctx.Config.Name, scope)
+	}
+	return ctx.Container.GetComponent(ctx.Config.Name)
+}